@@ -6,19 +6,14 @@ import (
 	"time"
 )
 
-func StartChat(db *gorm.DB, userID, JSON, fileLocation, initialMessage string, tokenEstimate int) (*JaiChat, error) {
-	jaiChat := JaiChat{
-		UserID:            userID,
-		JSON:              JSON,
-		FileLocation:      fileLocation,
-		FileTokenEstimate: tokenEstimate,
-		Model: gorm.Model{
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
+// StartChat saves the new chat together with the initial assistant message.
+func StartChat(db *gorm.DB, jaiChat *JaiChat, initialMessage string) (*JaiChat, error) {
+	jaiChat.Model = gorm.Model{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
-	err := db.Create(&jaiChat).Error
+	err := db.Create(jaiChat).Error
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +28,17 @@ func StartChat(db *gorm.DB, userID, JSON, fileLocation, initialMessage string, t
 		return nil, err
 	}
 
-	return &jaiChat, nil
+	return jaiChat, nil
+}
+
+// DeleteChat removes the chat together with its messages.
+func DeleteChat(db *gorm.DB, chatID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("jai_chat_id = ?", chatID).Delete(&ChatMessages{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", chatID).Delete(&JaiChat{}).Error
+	})
 }
 
 func GetUserChatCount(db *gorm.DB, userID string) (int64, error) {
@@ -83,3 +88,53 @@ func AddChatMessage(db *gorm.DB, message *ChatMessages) error {
 	err := db.Create(&message).Error
 	return err
 }
+
+func GetChatsToExpire(db *gorm.DB, now time.Time) ([]*JaiChat, error) {
+	var chats []*JaiChat
+	err := db.Where("expired = ? AND expires_at IS NOT NULL AND expires_at <= ?", false, now).Find(&chats).Error
+	if err != nil {
+		return nil, err
+	}
+	return chats, nil
+}
+
+// MarkChatExpired flags the chat as expired and clears what was derived from its data: the profile and schema
+// context of the chat, and the queries and result rows stored with its messages all include values of the data.
+func MarkChatExpired(db *gorm.DB, chatID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&JaiChat{}).Where("id = ?", chatID).Updates(map[string]interface{}{
+			"expired":         true,
+			"dataset_profile": "",
+			"schema_context":  "",
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&ChatMessages{}).Where("jai_chat_id = ?", chatID).Updates(map[string]interface{}{
+			"sql":      "",
+			"metadata": "",
+		}).Error
+	})
+}
+
+func SetChatDuckDB(db *gorm.DB, chatID, location, ingestionReport, datasetProfile, schemaContext string) error {
+	return db.Model(&JaiChat{}).Where("id = ?", chatID).Updates(map[string]interface{}{
+		"duck_db_location": location,
+		"ingestion_report": ingestionReport,
+		"dataset_profile":  datasetProfile,
+		"schema_context":   schemaContext,
+	}).Error
+}
+
+// SetChatHistorySummary saves the summary of the chat's messages up to and including the message with ID until.
+func SetChatHistorySummary(db *gorm.DB, chatID, summary string, until uint) error {
+	return db.Model(&JaiChat{}).Where("id = ?", chatID).Updates(map[string]interface{}{
+		"history_summary":       summary,
+		"history_summary_until": until,
+	}).Error
+}
+
+func SetChatSchemaContext(db *gorm.DB, chatID, schemaContext string) error {
+	return db.Model(&JaiChat{}).Where("id = ?", chatID).Update("schema_context", schemaContext).Error
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingConn records the statements gorm runs instead of sending them to a database.
+type recordingConn struct {
+	statements *[]string
+	inTx       bool
+}
+
+func (c *recordingConn) record(statement string) {
+	if c.inTx {
+		statement = "TX " + statement
+	}
+	*c.statements = append(*c.statements, statement)
+}
+
+func (c *recordingConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *recordingConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (c *recordingConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	c.record("BEGIN")
+	return &recordingTx{recordingConn{statements: c.statements, inTx: true}}, nil
+}
+
+type recordingTx struct {
+	recordingConn
+}
+
+func (t *recordingTx) Commit() error {
+	t.record("COMMIT")
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.record("ROLLBACK")
+	return nil
+}
+
+func openRecordingDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	statements := &[]string{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &recordingConn{statements: statements}}), &gorm.Config{
+		Logger:                 logger.Default.LogMode(logger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	return db, statements
+}
+
+func TestMarkChatExpiredClearsMessageResults(t *testing.T) {
+	db, statements := openRecordingDB(t)
+
+	if err := MarkChatExpired(db, "chat-1"); err != nil {
+		t.Fatalf("Failed to mark chat expired: %v", err)
+	}
+
+	want := []string{
+		`BEGIN`,
+		`TX UPDATE "jai_chats" SET "dataset_profile"=$1,"expired"=$2,"schema_context"=$3,"updated_at"=$4 WHERE id = $5`,
+		`TX UPDATE "chat_messages" SET "metadata"=$1,"sql"=$2,"updated_at"=$3 WHERE jai_chat_id = $4`,
+		`TX COMMIT`,
+	}
+	if len(*statements) != len(want) {
+		t.Fatalf("Got statements %q, want %q", *statements, want)
+	}
+	for i, statement := range *statements {
+		if !strings.HasPrefix(statement, want[i]) {
+			t.Errorf("Got statement %q, want %q", statement, want[i])
+		}
+	}
+}
@@ -38,3 +38,7 @@ func GetJsonFromCache(db *gorm.DB, chatID string) (*JSONCache, error) {
 	err := db.Where("jai_chat_id = ?", chatID).First(cache).Error
 	return cache, err
 }
+
+func DeleteJSONCache(db *gorm.DB, chatID string) error {
+	return db.Unscoped().Where("jai_chat_id = ?", chatID).Delete(&JSONCache{}).Error
+}
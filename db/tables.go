@@ -29,23 +29,45 @@ type User struct {
 	Pin              string    `gorm:"not null"`
 	TokensUsed       int       `gorm:"default:0"`
 	TokenLastRefresh time.Time `gorm:"default:now()"`
+	DataKey          string    // Data key wrapped by the master key, used to encrypt the user's uploads
+	DataKeyMasterID  string    // Fingerprint of the master key that wrapped DataKey
 	gorm.Model
 }
 
 type JaiChat struct {
 	UUID
-	UserID            string `gorm:"not null"`
-	JSON              string `gorm:"not null"`
-	FileLocation      string `gorm:"not null"`
-	FileTokenEstimate int    `gorm:"not null"`
+	UserID              string `gorm:"not null"`
+	JSON                string `gorm:"not null"`
+	FileLocation        string `gorm:"not null"`
+	FileTokenEstimate   int    `gorm:"not null"`
+	ExpiresAt           *time.Time
+	Expired             bool   `gorm:"default:false"`  // The uploaded data was deleted, the message history is kept
+	IngestMode          string `gorm:"default:nested"` // How nested arrays are loaded into DuckDB: nested or shred
+	RecordPath          string // JSON Pointer to the records chosen by the user, empty to detect them
+	DuckDBLocation      string // Prebuilt DuckDB database of the JSON, stored next to the uploaded file
+	IngestionReport     string `gorm:"type:text"` // JSON encoded report of the records loaded into DuckDB
+	DatasetProfile      string `gorm:"type:text"` // JSON encoded statistics of the columns loaded into DuckDB
+	SchemaContext       string `gorm:"type:text"` // JSON encoded, versioned description of the data for the prompts
+	LanguageModel       string // Model chosen at upload for every stage of answering, empty for the configured models
+	StageModels         string `gorm:"type:text"` // JSON encoded models chosen at upload for single stages, they take precedence over LanguageModel
+	HistorySummary      string `gorm:"type:text"` // Summary of the messages up to HistorySummaryUntil
+	HistorySummaryUntil uint   // ID of the last message included in HistorySummary
 	gorm.Model
 	User User `gorm:"foreignkey:UserID"`
 }
 
+// IsExpired reports whether the chat's uploaded data is past its retention period.
+func (c *JaiChat) IsExpired(now time.Time) bool {
+	return c.Expired || (c.ExpiresAt != nil && !c.ExpiresAt.After(now))
+}
+
 type ChatMessages struct {
 	JaiChatID string `gorm:"not null"`
 	Role      string `gorm:"not null"`
 	Message   string `gorm:"not null"`
+	Models    string `gorm:"type:text"` // JSON encoded models that produced an assistant message
+	SQL       string `gorm:"type:text"` // Query an assistant message was based on
+	Metadata  string `gorm:"type:text"` // JSON encoded failed queries, result rows and timings of an assistant message
 	gorm.Model
 	JaiChat JaiChat `gorm:"foreignkey:JaiChatID"`
 }
@@ -19,3 +19,29 @@ func GetUserByID(db *gorm.DB, userID string) (*User, error) {
 	}
 	return &user, nil
 }
+
+// SetUserDataKeyIfEmpty stores the wrapped data key unless the user already has one. It reports whether the key was stored.
+func SetUserDataKeyIfEmpty(db *gorm.DB, userID, dataKey, masterKeyID string) (bool, error) {
+	result := db.Model(&User{}).
+		Where("id = ? AND (data_key IS NULL OR data_key = '')", userID).
+		Updates(map[string]interface{}{"data_key": dataKey, "data_key_master_id": masterKeyID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func UpdateUserDataKey(db *gorm.DB, userID, dataKey, masterKeyID string) error {
+	return db.Model(&User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{"data_key": dataKey, "data_key_master_id": masterKeyID}).Error
+}
+
+func GetUsersWithDataKeys(db *gorm.DB) ([]*User, error) {
+	var users []*User
+	err := db.Where("data_key IS NOT NULL AND data_key <> ''").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -2,10 +2,21 @@ package main
 
 import (
 	"JsonAI/server"
+	"log"
+	"os"
 )
 
 func main() {
 	jaiServer := server.NewServer()
+
+	// go run main.go rotate-keys re-wraps all user data keys with the current master key
+	if len(os.Args) > 1 && os.Args[1] == "rotate-keys" {
+		if err := jaiServer.RotateDataKeys(); err != nil {
+			log.Fatalf("Failed to rotate data keys: %v", err)
+		}
+		return
+	}
+
 	err := jaiServer.Serve()
 	if err != nil {
 		return
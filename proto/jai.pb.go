@@ -237,6 +237,188 @@ func (*AskJsonAI) Descriptor() ([]byte, []int) {
 	return file_jai_proto_rawDescGZIP(), []int{5}
 }
 
+// AskJsonAIStream answers an AskJsonAI.Request with events: progress while the question is validated and queried,
+// the tokens of the answer as the model generates them and finally the complete response.
+type AskJsonAIStream struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AskJsonAIStream) Reset() {
+	*x = AskJsonAIStream{}
+	mi := &file_jai_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskJsonAIStream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskJsonAIStream) ProtoMessage() {}
+
+func (x *AskJsonAIStream) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskJsonAIStream.ProtoReflect.Descriptor instead.
+func (*AskJsonAIStream) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{6}
+}
+
+type GetChatFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetChatFile) Reset() {
+	*x = GetChatFile{}
+	mi := &file_jai_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatFile) ProtoMessage() {}
+
+func (x *GetChatFile) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatFile.ProtoReflect.Descriptor instead.
+func (*GetChatFile) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{7}
+}
+
+type GetUploadStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetUploadStatus) Reset() {
+	*x = GetUploadStatus{}
+	mi := &file_jai_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatus) ProtoMessage() {}
+
+func (x *GetUploadStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatus.ProtoReflect.Descriptor instead.
+func (*GetUploadStatus) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{8}
+}
+
+type GetIngestionReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetIngestionReport) Reset() {
+	*x = GetIngestionReport{}
+	mi := &file_jai_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIngestionReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIngestionReport) ProtoMessage() {}
+
+func (x *GetIngestionReport) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIngestionReport.ProtoReflect.Descriptor instead.
+func (*GetIngestionReport) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{9}
+}
+
+type GetDatasetProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetDatasetProfile) Reset() {
+	*x = GetDatasetProfile{}
+	mi := &file_jai_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatasetProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatasetProfile) ProtoMessage() {}
+
+func (x *GetDatasetProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatasetProfile.ProtoReflect.Descriptor instead.
+func (*GetDatasetProfile) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{10}
+}
+
 type SayHello_Request struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -247,7 +429,7 @@ type SayHello_Request struct {
 
 func (x *SayHello_Request) Reset() {
 	*x = SayHello_Request{}
-	mi := &file_jai_proto_msgTypes[6]
+	mi := &file_jai_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -259,7 +441,7 @@ func (x *SayHello_Request) String() string {
 func (*SayHello_Request) ProtoMessage() {}
 
 func (x *SayHello_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[6]
+	mi := &file_jai_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -292,7 +474,7 @@ type SayHello_Response struct {
 
 func (x *SayHello_Response) Reset() {
 	*x = SayHello_Response{}
-	mi := &file_jai_proto_msgTypes[7]
+	mi := &file_jai_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -304,7 +486,7 @@ func (x *SayHello_Response) String() string {
 func (*SayHello_Response) ProtoMessage() {}
 
 func (x *SayHello_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[7]
+	mi := &file_jai_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +520,7 @@ type Login_Request struct {
 
 func (x *Login_Request) Reset() {
 	*x = Login_Request{}
-	mi := &file_jai_proto_msgTypes[8]
+	mi := &file_jai_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -350,7 +532,7 @@ func (x *Login_Request) String() string {
 func (*Login_Request) ProtoMessage() {}
 
 func (x *Login_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[8]
+	mi := &file_jai_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +572,7 @@ type Login_Response struct {
 
 func (x *Login_Response) Reset() {
 	*x = Login_Response{}
-	mi := &file_jai_proto_msgTypes[9]
+	mi := &file_jai_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +584,7 @@ func (x *Login_Response) String() string {
 func (*Login_Response) ProtoMessage() {}
 
 func (x *Login_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[9]
+	mi := &file_jai_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +617,7 @@ type ListChats_Request struct {
 
 func (x *ListChats_Request) Reset() {
 	*x = ListChats_Request{}
-	mi := &file_jai_proto_msgTypes[10]
+	mi := &file_jai_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -447,7 +629,7 @@ func (x *ListChats_Request) String() string {
 func (*ListChats_Request) ProtoMessage() {}
 
 func (x *ListChats_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[10]
+	mi := &file_jai_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -480,7 +662,7 @@ type ListChats_Response struct {
 
 func (x *ListChats_Response) Reset() {
 	*x = ListChats_Response{}
-	mi := &file_jai_proto_msgTypes[11]
+	mi := &file_jai_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -492,7 +674,7 @@ func (x *ListChats_Response) String() string {
 func (*ListChats_Response) ProtoMessage() {}
 
 func (x *ListChats_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[11]
+	mi := &file_jai_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -527,7 +709,7 @@ type UploadJson_Request struct {
 
 func (x *UploadJson_Request) Reset() {
 	*x = UploadJson_Request{}
-	mi := &file_jai_proto_msgTypes[12]
+	mi := &file_jai_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -539,7 +721,7 @@ func (x *UploadJson_Request) String() string {
 func (*UploadJson_Request) ProtoMessage() {}
 
 func (x *UploadJson_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[12]
+	mi := &file_jai_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -586,7 +768,7 @@ type UploadJson_Response struct {
 
 func (x *UploadJson_Response) Reset() {
 	*x = UploadJson_Response{}
-	mi := &file_jai_proto_msgTypes[13]
+	mi := &file_jai_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -598,7 +780,7 @@ func (x *UploadJson_Response) String() string {
 func (*UploadJson_Response) ProtoMessage() {}
 
 func (x *UploadJson_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[13]
+	mi := &file_jai_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -632,7 +814,7 @@ type GetChat_Request struct {
 
 func (x *GetChat_Request) Reset() {
 	*x = GetChat_Request{}
-	mi := &file_jai_proto_msgTypes[14]
+	mi := &file_jai_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -644,7 +826,7 @@ func (x *GetChat_Request) String() string {
 func (*GetChat_Request) ProtoMessage() {}
 
 func (x *GetChat_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[14]
+	mi := &file_jai_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -684,7 +866,7 @@ type GetChat_Response struct {
 
 func (x *GetChat_Response) Reset() {
 	*x = GetChat_Response{}
-	mi := &file_jai_proto_msgTypes[15]
+	mi := &file_jai_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -696,7 +878,7 @@ func (x *GetChat_Response) String() string {
 func (*GetChat_Response) ProtoMessage() {}
 
 func (x *GetChat_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[15]
+	mi := &file_jai_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -724,14 +906,16 @@ type AskJsonAI_Request struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserID   string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
-	ChatID   string `protobuf:"bytes,2,opt,name=chatID,proto3" json:"chatID,omitempty"`
-	Question string `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	UserID   string       `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	ChatID   string       `protobuf:"bytes,2,opt,name=chatID,proto3" json:"chatID,omitempty"`
+	Question string       `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Model    string       `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`   // Overrides the model of the chat for this question, must be one of the allowed models
+	Models   *StageModels `protobuf:"bytes,5,opt,name=models,proto3" json:"models,omitempty"` // Overrides the models of single stages for this question, they take precedence over model
 }
 
 func (x *AskJsonAI_Request) Reset() {
 	*x = AskJsonAI_Request{}
-	mi := &file_jai_proto_msgTypes[16]
+	mi := &file_jai_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -743,7 +927,7 @@ func (x *AskJsonAI_Request) String() string {
 func (*AskJsonAI_Request) ProtoMessage() {}
 
 func (x *AskJsonAI_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[16]
+	mi := &file_jai_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -780,18 +964,34 @@ func (x *AskJsonAI_Request) GetQuestion() string {
 	return ""
 }
 
+func (x *AskJsonAI_Request) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AskJsonAI_Request) GetModels() *StageModels {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
 type AskJsonAI_Response struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Answer string `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
-	Chat   *Chat  `protobuf:"bytes,2,opt,name=chat,proto3" json:"chat,omitempty"` // Full chat history
+	Answer      string       `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Chat        *Chat        `protobuf:"bytes,2,opt,name=chat,proto3" json:"chat,omitempty"`               // Full chat history
+	ResultTable *ResultTable `protobuf:"bytes,3,opt,name=resultTable,proto3" json:"resultTable,omitempty"` // Only set when the answer is based on a query
+	Sql         string       `protobuf:"bytes,4,opt,name=sql,proto3" json:"sql,omitempty"`                 // The query the answer is based on
 }
 
 func (x *AskJsonAI_Response) Reset() {
 	*x = AskJsonAI_Response{}
-	mi := &file_jai_proto_msgTypes[17]
+	mi := &file_jai_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -803,7 +1003,7 @@ func (x *AskJsonAI_Response) String() string {
 func (*AskJsonAI_Response) ProtoMessage() {}
 
 func (x *AskJsonAI_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_jai_proto_msgTypes[17]
+	mi := &file_jai_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,166 +1033,971 @@ func (x *AskJsonAI_Response) GetChat() *Chat {
 	return nil
 }
 
-var File_jai_proto protoreflect.FileDescriptor
+func (x *AskJsonAI_Response) GetResultTable() *ResultTable {
+	if x != nil {
+		return x.ResultTable
+	}
+	return nil
+}
 
-var file_jai_proto_rawDesc = []byte{
-	0x0a, 0x09, 0x6a, 0x61, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61,
-	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x1a, 0x0d, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0x4f, 0x0a, 0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x1a, 0x1d, 0x0a, 0x07, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x1a, 0x24, 0x0a, 0x08, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x67, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x1a, 0x31, 0x0a, 0x07, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x69, 0x6e, 0x1a, 0x2b, 0x0a, 0x08,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55,
-	0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x5d, 0x0a, 0x09, 0x4c, 0x69, 0x73,
-	0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x1a, 0x21, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x2d, 0x0a, 0x08, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61,
-	0x74, 0x52, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x0a, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x1a, 0x59, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69,
-	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69,
-	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x44, 0x61,
-	0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x44, 0x61,
-	0x74, 0x61, 0x1a, 0x2b, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
-	0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68, 0x61, 0x74, 0x22,
-	0x71, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x1a, 0x39, 0x0a, 0x07, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a,
-	0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
-	0x68, 0x61, 0x74, 0x49, 0x44, 0x1a, 0x2b, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1f, 0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68,
-	0x61, 0x74, 0x22, 0xa7, 0x01, 0x0a, 0x09, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49,
-	0x1a, 0x55, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x43, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x04, 0x63,
-	0x68, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68, 0x61, 0x74, 0x32, 0x82, 0x04, 0x0a,
-	0x0d, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5b,
-	0x0a, 0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x2e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x61, 0x79, 0x48,
-	0x65, 0x6c, 0x6c, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x82,
-	0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d,
-	0x61, 0x69, 0x2f, 0x73, 0x61, 0x79, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x4f, 0x0a, 0x05, 0x4c,
-	0x6f, 0x67, 0x69, 0x6e, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67,
-	0x69, 0x6e, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x3a, 0x01, 0x2a, 0x22, 0x0e, 0x2f, 0x6a,
-	0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x66, 0x0a, 0x09,
-	0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x43, 0x68, 0x61, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x12, 0x1c, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69,
-	0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d, 0x2f, 0x63,
-	0x68, 0x61, 0x74, 0x73, 0x12, 0x68, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x12,
-	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x2e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x12, 0x24, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d,
-	0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d,
-	0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x7d, 0x12, 0x71,
-	0x0a, 0x09, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x12, 0x18, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73,
-	0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x2f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x29, 0x3a, 0x01, 0x2a, 0x1a, 0x24, 0x2f, 0x6a, 0x73,
-	0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x44, 0x7d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44,
-	0x7d, 0x42, 0x1d, 0x5a, 0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x61, 0x76, 0x69, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x61, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *AskJsonAI_Response) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
 }
 
-var (
-	file_jai_proto_rawDescOnce sync.Once
-	file_jai_proto_rawDescData = file_jai_proto_rawDesc
-)
+type AskJsonAIStream_Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_jai_proto_rawDescGZIP() []byte {
-	file_jai_proto_rawDescOnce.Do(func() {
-		file_jai_proto_rawDescData = protoimpl.X.CompressGZIP(file_jai_proto_rawDescData)
-	})
-	return file_jai_proto_rawDescData
+	// Types that are assignable to Event:
+	//	*AskJsonAIStream_Event_Progress
+	//	*AskJsonAIStream_Event_Token
+	//	*AskJsonAIStream_Event_Done
+	Event isAskJsonAIStream_Event_Event `protobuf_oneof:"event"`
 }
 
-var file_jai_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
-var file_jai_proto_goTypes = []any{
-	(*SayHello)(nil),            // 0: proto.SayHello
-	(*Login)(nil),               // 1: proto.Login
-	(*ListChats)(nil),           // 2: proto.ListChats
-	(*UploadJson)(nil),          // 3: proto.UploadJson
-	(*GetChat)(nil),             // 4: proto.GetChat
-	(*AskJsonAI)(nil),           // 5: proto.AskJsonAI
-	(*SayHello_Request)(nil),    // 6: proto.SayHello.Request
-	(*SayHello_Response)(nil),   // 7: proto.SayHello.Response
-	(*Login_Request)(nil),       // 8: proto.Login.Request
-	(*Login_Response)(nil),      // 9: proto.Login.Response
-	(*ListChats_Request)(nil),   // 10: proto.ListChats.Request
-	(*ListChats_Response)(nil),  // 11: proto.ListChats.Response
-	(*UploadJson_Request)(nil),  // 12: proto.UploadJson.Request
-	(*UploadJson_Response)(nil), // 13: proto.UploadJson.Response
-	(*GetChat_Request)(nil),     // 14: proto.GetChat.Request
-	(*GetChat_Response)(nil),    // 15: proto.GetChat.Response
-	(*AskJsonAI_Request)(nil),   // 16: proto.AskJsonAI.Request
-	(*AskJsonAI_Response)(nil),  // 17: proto.AskJsonAI.Response
-	(*User)(nil),                // 18: proto.User
-	(*Chat)(nil),                // 19: proto.Chat
+func (x *AskJsonAIStream_Event) Reset() {
+	*x = AskJsonAIStream_Event{}
+	mi := &file_jai_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-var file_jai_proto_depIdxs = []int32{
-	18, // 0: proto.Login.Response.user:type_name -> proto.User
-	19, // 1: proto.ListChats.Response.chats:type_name -> proto.Chat
-	19, // 2: proto.UploadJson.Response.chat:type_name -> proto.Chat
-	19, // 3: proto.GetChat.Response.chat:type_name -> proto.Chat
-	19, // 4: proto.AskJsonAI.Response.chat:type_name -> proto.Chat
-	6,  // 5: proto.JsonAIService.SayHello:input_type -> proto.SayHello.Request
-	8,  // 6: proto.JsonAIService.Login:input_type -> proto.Login.Request
-	10, // 7: proto.JsonAIService.ListChats:input_type -> proto.ListChats.Request
-	14, // 8: proto.JsonAIService.GetChat:input_type -> proto.GetChat.Request
-	16, // 9: proto.JsonAIService.AskJsonAI:input_type -> proto.AskJsonAI.Request
-	7,  // 10: proto.JsonAIService.SayHello:output_type -> proto.SayHello.Response
-	9,  // 11: proto.JsonAIService.Login:output_type -> proto.Login.Response
-	11, // 12: proto.JsonAIService.ListChats:output_type -> proto.ListChats.Response
-	15, // 13: proto.JsonAIService.GetChat:output_type -> proto.GetChat.Response
-	17, // 14: proto.JsonAIService.AskJsonAI:output_type -> proto.AskJsonAI.Response
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+
+func (x *AskJsonAIStream_Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_jai_proto_init() }
-func file_jai_proto_init() {
-	if File_jai_proto != nil {
-		return
-	}
+func (*AskJsonAIStream_Event) ProtoMessage() {}
+
+func (x *AskJsonAIStream_Event) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskJsonAIStream_Event.ProtoReflect.Descriptor instead.
+func (*AskJsonAIStream_Event) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (m *AskJsonAIStream_Event) GetEvent() isAskJsonAIStream_Event_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *AskJsonAIStream_Event) GetProgress() *AskJsonAIStream_Progress {
+	if x, ok := x.GetEvent().(*AskJsonAIStream_Event_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *AskJsonAIStream_Event) GetToken() string {
+	if x, ok := x.GetEvent().(*AskJsonAIStream_Event_Token); ok {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *AskJsonAIStream_Event) GetDone() *AskJsonAI_Response {
+	if x, ok := x.GetEvent().(*AskJsonAIStream_Event_Done); ok {
+		return x.Done
+	}
+	return nil
+}
+
+type isAskJsonAIStream_Event_Event interface {
+	isAskJsonAIStream_Event_Event()
+}
+
+type AskJsonAIStream_Event_Progress struct {
+	Progress *AskJsonAIStream_Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type AskJsonAIStream_Event_Token struct {
+	Token string `protobuf:"bytes,2,opt,name=token,proto3,oneof"` // Next piece of the answer
+}
+
+type AskJsonAIStream_Event_Done struct {
+	Done *AskJsonAI_Response `protobuf:"bytes,3,opt,name=done,proto3,oneof"` // Sent last, once the answer was saved to the chat
+}
+
+func (*AskJsonAIStream_Event_Progress) isAskJsonAIStream_Event_Event() {}
+
+func (*AskJsonAIStream_Event_Token) isAskJsonAIStream_Event_Event() {}
+
+func (*AskJsonAIStream_Event_Done) isAskJsonAIStream_Event_Event() {}
+
+type AskJsonAIStream_Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stage   string `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`      // summarizing_history, validating, generating_sql, running_query, query_failed or answering
+	Attempt int32  `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"` // Attempt of the SQL stages, starting at 1
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`  // The query being run, or why it failed
+}
+
+func (x *AskJsonAIStream_Progress) Reset() {
+	*x = AskJsonAIStream_Progress{}
+	mi := &file_jai_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskJsonAIStream_Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskJsonAIStream_Progress) ProtoMessage() {}
+
+func (x *AskJsonAIStream_Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskJsonAIStream_Progress.ProtoReflect.Descriptor instead.
+func (*AskJsonAIStream_Progress) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{6, 1}
+}
+
+func (x *AskJsonAIStream_Progress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *AskJsonAIStream_Progress) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *AskJsonAIStream_Progress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetChatFile_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	ChatID string `protobuf:"bytes,2,opt,name=chatID,proto3" json:"chatID,omitempty"`
+}
+
+func (x *GetChatFile_Request) Reset() {
+	*x = GetChatFile_Request{}
+	mi := &file_jai_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatFile_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatFile_Request) ProtoMessage() {}
+
+func (x *GetChatFile_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatFile_Request.ProtoReflect.Descriptor instead.
+func (*GetChatFile_Request) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *GetChatFile_Request) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetChatFile_Request) GetChatID() string {
+	if x != nil {
+		return x.ChatID
+	}
+	return ""
+}
+
+type GetChatFile_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileName    string `protobuf:"bytes,1,opt,name=fileName,proto3" json:"fileName,omitempty"`
+	Size        int64  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"` // Size of the original file in bytes
+	DownloadURL string `protobuf:"bytes,3,opt,name=downloadURL,proto3" json:"downloadURL,omitempty"`
+	ExpiresAt   string `protobuf:"bytes,4,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	Presigned   bool   `protobuf:"varint,5,opt,name=presigned,proto3" json:"presigned,omitempty"` // False when the file is streamed through the server instead of S3
+}
+
+func (x *GetChatFile_Response) Reset() {
+	*x = GetChatFile_Response{}
+	mi := &file_jai_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatFile_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatFile_Response) ProtoMessage() {}
+
+func (x *GetChatFile_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatFile_Response.ProtoReflect.Descriptor instead.
+func (*GetChatFile_Response) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{7, 1}
+}
+
+func (x *GetChatFile_Response) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *GetChatFile_Response) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetChatFile_Response) GetDownloadURL() string {
+	if x != nil {
+		return x.DownloadURL
+	}
+	return ""
+}
+
+func (x *GetChatFile_Response) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *GetChatFile_Response) GetPresigned() bool {
+	if x != nil {
+		return x.Presigned
+	}
+	return false
+}
+
+type GetUploadStatus_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID   string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	UploadID string `protobuf:"bytes,2,opt,name=uploadID,proto3" json:"uploadID,omitempty"`
+}
+
+func (x *GetUploadStatus_Request) Reset() {
+	*x = GetUploadStatus_Request{}
+	mi := &file_jai_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatus_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatus_Request) ProtoMessage() {}
+
+func (x *GetUploadStatus_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatus_Request.ProtoReflect.Descriptor instead.
+func (*GetUploadStatus_Request) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *GetUploadStatus_Request) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetUploadStatus_Request) GetUploadID() string {
+	if x != nil {
+		return x.UploadID
+	}
+	return ""
+}
+
+type GetUploadStatus_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UploadID       string `protobuf:"bytes,1,opt,name=uploadID,proto3" json:"uploadID,omitempty"`
+	State          string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // uploading, processing, completed or failed
+	BytesUploaded  int64  `protobuf:"varint,3,opt,name=bytesUploaded,proto3" json:"bytesUploaded,omitempty"`
+	TotalBytes     int64  `protobuf:"varint,4,opt,name=totalBytes,proto3" json:"totalBytes,omitempty"`
+	PartsCompleted int32  `protobuf:"varint,5,opt,name=partsCompleted,proto3" json:"partsCompleted,omitempty"`
+	TotalParts     int32  `protobuf:"varint,6,opt,name=totalParts,proto3" json:"totalParts,omitempty"`
+	ChatID         string `protobuf:"bytes,7,opt,name=chatID,proto3" json:"chatID,omitempty"` // Set once the upload completed and the chat was created
+	Error          string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *GetUploadStatus_Response) Reset() {
+	*x = GetUploadStatus_Response{}
+	mi := &file_jai_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatus_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatus_Response) ProtoMessage() {}
+
+func (x *GetUploadStatus_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatus_Response.ProtoReflect.Descriptor instead.
+func (*GetUploadStatus_Response) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{8, 1}
+}
+
+func (x *GetUploadStatus_Response) GetUploadID() string {
+	if x != nil {
+		return x.UploadID
+	}
+	return ""
+}
+
+func (x *GetUploadStatus_Response) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GetUploadStatus_Response) GetBytesUploaded() int64 {
+	if x != nil {
+		return x.BytesUploaded
+	}
+	return 0
+}
+
+func (x *GetUploadStatus_Response) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *GetUploadStatus_Response) GetPartsCompleted() int32 {
+	if x != nil {
+		return x.PartsCompleted
+	}
+	return 0
+}
+
+func (x *GetUploadStatus_Response) GetTotalParts() int32 {
+	if x != nil {
+		return x.TotalParts
+	}
+	return 0
+}
+
+func (x *GetUploadStatus_Response) GetChatID() string {
+	if x != nil {
+		return x.ChatID
+	}
+	return ""
+}
+
+func (x *GetUploadStatus_Response) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetIngestionReport_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	ChatID string `protobuf:"bytes,2,opt,name=chatID,proto3" json:"chatID,omitempty"`
+}
+
+func (x *GetIngestionReport_Request) Reset() {
+	*x = GetIngestionReport_Request{}
+	mi := &file_jai_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIngestionReport_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIngestionReport_Request) ProtoMessage() {}
+
+func (x *GetIngestionReport_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIngestionReport_Request.ProtoReflect.Descriptor instead.
+func (*GetIngestionReport_Request) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *GetIngestionReport_Request) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetIngestionReport_Request) GetChatID() string {
+	if x != nil {
+		return x.ChatID
+	}
+	return ""
+}
+
+type GetIngestionReport_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Report *IngestionReport `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+}
+
+func (x *GetIngestionReport_Response) Reset() {
+	*x = GetIngestionReport_Response{}
+	mi := &file_jai_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIngestionReport_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIngestionReport_Response) ProtoMessage() {}
+
+func (x *GetIngestionReport_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIngestionReport_Response.ProtoReflect.Descriptor instead.
+func (*GetIngestionReport_Response) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{9, 1}
+}
+
+func (x *GetIngestionReport_Response) GetReport() *IngestionReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type GetDatasetProfile_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	ChatID string `protobuf:"bytes,2,opt,name=chatID,proto3" json:"chatID,omitempty"`
+}
+
+func (x *GetDatasetProfile_Request) Reset() {
+	*x = GetDatasetProfile_Request{}
+	mi := &file_jai_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatasetProfile_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatasetProfile_Request) ProtoMessage() {}
+
+func (x *GetDatasetProfile_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatasetProfile_Request.ProtoReflect.Descriptor instead.
+func (*GetDatasetProfile_Request) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *GetDatasetProfile_Request) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetDatasetProfile_Request) GetChatID() string {
+	if x != nil {
+		return x.ChatID
+	}
+	return ""
+}
+
+type GetDatasetProfile_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Profile *DatasetProfile `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+}
+
+func (x *GetDatasetProfile_Response) Reset() {
+	*x = GetDatasetProfile_Response{}
+	mi := &file_jai_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatasetProfile_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatasetProfile_Response) ProtoMessage() {}
+
+func (x *GetDatasetProfile_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_jai_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatasetProfile_Response.ProtoReflect.Descriptor instead.
+func (*GetDatasetProfile_Response) Descriptor() ([]byte, []int) {
+	return file_jai_proto_rawDescGZIP(), []int{10, 1}
+}
+
+func (x *GetDatasetProfile_Response) GetProfile() *DatasetProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+var File_jai_proto protoreflect.FileDescriptor
+
+var file_jai_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x6a, 0x61, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x0d, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x4f, 0x0a, 0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x1a, 0x1d, 0x0a, 0x07, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x1a, 0x24, 0x0a, 0x08, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x67, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x1a, 0x31, 0x0a, 0x07, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x69, 0x6e, 0x1a, 0x2b, 0x0a, 0x08,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x5d, 0x0a, 0x09, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x1a, 0x21, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x2d, 0x0a, 0x08, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61,
+	0x74, 0x52, 0x05, 0x63, 0x68, 0x61, 0x74, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x0a, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x4a, 0x73, 0x6f, 0x6e, 0x1a, 0x59, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69,
+	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69,
+	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x1a, 0x2b, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
+	0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68, 0x61, 0x74, 0x22,
+	0x71, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x1a, 0x39, 0x0a, 0x07, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x68, 0x61, 0x74, 0x49, 0x44, 0x1a, 0x2b, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1f, 0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68,
+	0x61, 0x74, 0x22, 0xb3, 0x02, 0x0a, 0x09, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49,
+	0x1a, 0x97, 0x01, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x2a,
+	0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x73, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x1a, 0x8b, 0x01, 0x0a, 0x08, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x04, 0x63, 0x68, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x63, 0x68, 0x61, 0x74,
+	0x12, 0x34, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x71, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x71, 0x6c, 0x22, 0x82, 0x02, 0x0a, 0x0f, 0x41, 0x73, 0x6b,
+	0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x1a, 0x98, 0x01, 0x0a,
+	0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3d, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x2f, 0x0a,
+	0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x54, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xe3, 0x01,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x1a, 0x39, 0x0a,
+	0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x1a, 0x98, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x55, 0x52, 0x4c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x6f, 0x77, 0x6e,
+	0x6c, 0x6f, 0x61, 0x64, 0x55, 0x52, 0x4c, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x65, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x22, 0xcb, 0x02, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a, 0x3d, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x49, 0x44, 0x1a, 0xf8, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x44, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x70,
+	0x61, 0x72, 0x74, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61,
+	0x72, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x8b, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x1a, 0x39, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x68, 0x61, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61,
+	0x74, 0x49, 0x44, 0x1a, 0x3a, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2e, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x22,
+	0x8b, 0x01, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x1a, 0x39, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74,
+	0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44,
+	0x1a, 0x3b, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x32, 0x86, 0x09,
+	0x0a, 0x0d, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x5b, 0x0a, 0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x61, 0x79,
+	0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x6a, 0x73, 0x6f, 0x6e,
+	0x2d, 0x61, 0x69, 0x2f, 0x73, 0x61, 0x79, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x4f, 0x0a, 0x05,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x3a, 0x01, 0x2a, 0x22, 0x0e, 0x2f,
+	0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x66, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x68, 0x61, 0x74, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x12, 0x1c, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61,
+	0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d, 0x2f,
+	0x63, 0x68, 0x61, 0x74, 0x73, 0x12, 0x68, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74,
+	0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74,
+	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x12, 0x24, 0x2f, 0x6a, 0x73, 0x6f, 0x6e,
+	0x2d, 0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x7d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x7d, 0x12,
+	0x71, 0x0a, 0x09, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x12, 0x18, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41,
+	0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x2f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x29, 0x3a, 0x01, 0x2a, 0x1a, 0x24, 0x2f, 0x6a,
+	0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x7d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61, 0x74, 0x49,
+	0x44, 0x7d, 0x12, 0x4b, 0x0a, 0x0f, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73,
+	0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41, 0x49, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x73, 0x6b, 0x4a, 0x73, 0x6f, 0x6e, 0x41,
+	0x49, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12,
+	0x79, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1a,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x31, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2b, 0x12,
+	0x29, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68,
+	0x61, 0x74, 0x49, 0x44, 0x7d, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x8b, 0x01, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x37, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x31, 0x12, 0x2f, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61,
+	0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d, 0x2f,
+	0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x7b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x44,
+	0x7d, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x9a, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x37, 0x12, 0x35,
+	0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d, 0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x7d, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61,
+	0x74, 0x49, 0x44, 0x7d, 0x2f, 0x69, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x2d, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x8e, 0x01, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x34, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2e, 0x12, 0x2c, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x2d,
+	0x61, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x7d,
+	0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f, 0x7b, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x7d, 0x2f, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x1d, 0x5a, 0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x69, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x61, 0x69, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_jai_proto_rawDescOnce sync.Once
+	file_jai_proto_rawDescData = file_jai_proto_rawDesc
+)
+
+func file_jai_proto_rawDescGZIP() []byte {
+	file_jai_proto_rawDescOnce.Do(func() {
+		file_jai_proto_rawDescData = protoimpl.X.CompressGZIP(file_jai_proto_rawDescData)
+	})
+	return file_jai_proto_rawDescData
+}
+
+var file_jai_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
+var file_jai_proto_goTypes = []any{
+	(*SayHello)(nil),                    // 0: proto.SayHello
+	(*Login)(nil),                       // 1: proto.Login
+	(*ListChats)(nil),                   // 2: proto.ListChats
+	(*UploadJson)(nil),                  // 3: proto.UploadJson
+	(*GetChat)(nil),                     // 4: proto.GetChat
+	(*AskJsonAI)(nil),                   // 5: proto.AskJsonAI
+	(*AskJsonAIStream)(nil),             // 6: proto.AskJsonAIStream
+	(*GetChatFile)(nil),                 // 7: proto.GetChatFile
+	(*GetUploadStatus)(nil),             // 8: proto.GetUploadStatus
+	(*GetIngestionReport)(nil),          // 9: proto.GetIngestionReport
+	(*GetDatasetProfile)(nil),           // 10: proto.GetDatasetProfile
+	(*SayHello_Request)(nil),            // 11: proto.SayHello.Request
+	(*SayHello_Response)(nil),           // 12: proto.SayHello.Response
+	(*Login_Request)(nil),               // 13: proto.Login.Request
+	(*Login_Response)(nil),              // 14: proto.Login.Response
+	(*ListChats_Request)(nil),           // 15: proto.ListChats.Request
+	(*ListChats_Response)(nil),          // 16: proto.ListChats.Response
+	(*UploadJson_Request)(nil),          // 17: proto.UploadJson.Request
+	(*UploadJson_Response)(nil),         // 18: proto.UploadJson.Response
+	(*GetChat_Request)(nil),             // 19: proto.GetChat.Request
+	(*GetChat_Response)(nil),            // 20: proto.GetChat.Response
+	(*AskJsonAI_Request)(nil),           // 21: proto.AskJsonAI.Request
+	(*AskJsonAI_Response)(nil),          // 22: proto.AskJsonAI.Response
+	(*AskJsonAIStream_Event)(nil),       // 23: proto.AskJsonAIStream.Event
+	(*AskJsonAIStream_Progress)(nil),    // 24: proto.AskJsonAIStream.Progress
+	(*GetChatFile_Request)(nil),         // 25: proto.GetChatFile.Request
+	(*GetChatFile_Response)(nil),        // 26: proto.GetChatFile.Response
+	(*GetUploadStatus_Request)(nil),     // 27: proto.GetUploadStatus.Request
+	(*GetUploadStatus_Response)(nil),    // 28: proto.GetUploadStatus.Response
+	(*GetIngestionReport_Request)(nil),  // 29: proto.GetIngestionReport.Request
+	(*GetIngestionReport_Response)(nil), // 30: proto.GetIngestionReport.Response
+	(*GetDatasetProfile_Request)(nil),   // 31: proto.GetDatasetProfile.Request
+	(*GetDatasetProfile_Response)(nil),  // 32: proto.GetDatasetProfile.Response
+	(*User)(nil),                        // 33: proto.User
+	(*Chat)(nil),                        // 34: proto.Chat
+	(*StageModels)(nil),                 // 35: proto.StageModels
+	(*ResultTable)(nil),                 // 36: proto.ResultTable
+	(*IngestionReport)(nil),             // 37: proto.IngestionReport
+	(*DatasetProfile)(nil),              // 38: proto.DatasetProfile
+}
+var file_jai_proto_depIdxs = []int32{
+	33, // 0: proto.Login.Response.user:type_name -> proto.User
+	34, // 1: proto.ListChats.Response.chats:type_name -> proto.Chat
+	34, // 2: proto.UploadJson.Response.chat:type_name -> proto.Chat
+	34, // 3: proto.GetChat.Response.chat:type_name -> proto.Chat
+	35, // 4: proto.AskJsonAI.Request.models:type_name -> proto.StageModels
+	34, // 5: proto.AskJsonAI.Response.chat:type_name -> proto.Chat
+	36, // 6: proto.AskJsonAI.Response.resultTable:type_name -> proto.ResultTable
+	24, // 7: proto.AskJsonAIStream.Event.progress:type_name -> proto.AskJsonAIStream.Progress
+	22, // 8: proto.AskJsonAIStream.Event.done:type_name -> proto.AskJsonAI.Response
+	37, // 9: proto.GetIngestionReport.Response.report:type_name -> proto.IngestionReport
+	38, // 10: proto.GetDatasetProfile.Response.profile:type_name -> proto.DatasetProfile
+	11, // 11: proto.JsonAIService.SayHello:input_type -> proto.SayHello.Request
+	13, // 12: proto.JsonAIService.Login:input_type -> proto.Login.Request
+	15, // 13: proto.JsonAIService.ListChats:input_type -> proto.ListChats.Request
+	19, // 14: proto.JsonAIService.GetChat:input_type -> proto.GetChat.Request
+	21, // 15: proto.JsonAIService.AskJsonAI:input_type -> proto.AskJsonAI.Request
+	21, // 16: proto.JsonAIService.AskJsonAIStream:input_type -> proto.AskJsonAI.Request
+	25, // 17: proto.JsonAIService.GetChatFile:input_type -> proto.GetChatFile.Request
+	27, // 18: proto.JsonAIService.GetUploadStatus:input_type -> proto.GetUploadStatus.Request
+	29, // 19: proto.JsonAIService.GetIngestionReport:input_type -> proto.GetIngestionReport.Request
+	31, // 20: proto.JsonAIService.GetDatasetProfile:input_type -> proto.GetDatasetProfile.Request
+	12, // 21: proto.JsonAIService.SayHello:output_type -> proto.SayHello.Response
+	14, // 22: proto.JsonAIService.Login:output_type -> proto.Login.Response
+	16, // 23: proto.JsonAIService.ListChats:output_type -> proto.ListChats.Response
+	20, // 24: proto.JsonAIService.GetChat:output_type -> proto.GetChat.Response
+	22, // 25: proto.JsonAIService.AskJsonAI:output_type -> proto.AskJsonAI.Response
+	23, // 26: proto.JsonAIService.AskJsonAIStream:output_type -> proto.AskJsonAIStream.Event
+	26, // 27: proto.JsonAIService.GetChatFile:output_type -> proto.GetChatFile.Response
+	28, // 28: proto.JsonAIService.GetUploadStatus:output_type -> proto.GetUploadStatus.Response
+	30, // 29: proto.JsonAIService.GetIngestionReport:output_type -> proto.GetIngestionReport.Response
+	32, // 30: proto.JsonAIService.GetDatasetProfile:output_type -> proto.GetDatasetProfile.Response
+	21, // [21:31] is the sub-list for method output_type
+	11, // [11:21] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_jai_proto_init() }
+func file_jai_proto_init() {
+	if File_jai_proto != nil {
+		return
+	}
 	file_objects_proto_init()
+	file_jai_proto_msgTypes[23].OneofWrappers = []any{
+		(*AskJsonAIStream_Event_Progress)(nil),
+		(*AskJsonAIStream_Event_Token)(nil),
+		(*AskJsonAIStream_Event_Done)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_jai_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
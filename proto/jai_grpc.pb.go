@@ -19,11 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	JsonAIService_SayHello_FullMethodName  = "/proto.JsonAIService/SayHello"
-	JsonAIService_Login_FullMethodName     = "/proto.JsonAIService/Login"
-	JsonAIService_ListChats_FullMethodName = "/proto.JsonAIService/ListChats"
-	JsonAIService_GetChat_FullMethodName   = "/proto.JsonAIService/GetChat"
-	JsonAIService_AskJsonAI_FullMethodName = "/proto.JsonAIService/AskJsonAI"
+	JsonAIService_SayHello_FullMethodName           = "/proto.JsonAIService/SayHello"
+	JsonAIService_Login_FullMethodName              = "/proto.JsonAIService/Login"
+	JsonAIService_ListChats_FullMethodName          = "/proto.JsonAIService/ListChats"
+	JsonAIService_GetChat_FullMethodName            = "/proto.JsonAIService/GetChat"
+	JsonAIService_AskJsonAI_FullMethodName          = "/proto.JsonAIService/AskJsonAI"
+	JsonAIService_AskJsonAIStream_FullMethodName    = "/proto.JsonAIService/AskJsonAIStream"
+	JsonAIService_GetChatFile_FullMethodName        = "/proto.JsonAIService/GetChatFile"
+	JsonAIService_GetUploadStatus_FullMethodName    = "/proto.JsonAIService/GetUploadStatus"
+	JsonAIService_GetIngestionReport_FullMethodName = "/proto.JsonAIService/GetIngestionReport"
+	JsonAIService_GetDatasetProfile_FullMethodName  = "/proto.JsonAIService/GetDatasetProfile"
 )
 
 // JsonAIServiceClient is the client API for JsonAIService service.
@@ -35,6 +40,13 @@ type JsonAIServiceClient interface {
 	ListChats(ctx context.Context, in *ListChats_Request, opts ...grpc.CallOption) (*ListChats_Response, error)
 	GetChat(ctx context.Context, in *GetChat_Request, opts ...grpc.CallOption) (*GetChat_Response, error)
 	AskJsonAI(ctx context.Context, in *AskJsonAI_Request, opts ...grpc.CallOption) (*AskJsonAI_Response, error)
+	// Not exposed through the gateway, HTTP clients stream over server-sent events from
+	// POST /json-ai/user/{userID}/chat/{chatID}/stream
+	AskJsonAIStream(ctx context.Context, in *AskJsonAI_Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AskJsonAIStream_Event], error)
+	GetChatFile(ctx context.Context, in *GetChatFile_Request, opts ...grpc.CallOption) (*GetChatFile_Response, error)
+	GetUploadStatus(ctx context.Context, in *GetUploadStatus_Request, opts ...grpc.CallOption) (*GetUploadStatus_Response, error)
+	GetIngestionReport(ctx context.Context, in *GetIngestionReport_Request, opts ...grpc.CallOption) (*GetIngestionReport_Response, error)
+	GetDatasetProfile(ctx context.Context, in *GetDatasetProfile_Request, opts ...grpc.CallOption) (*GetDatasetProfile_Response, error)
 }
 
 type jsonAIServiceClient struct {
@@ -95,6 +107,65 @@ func (c *jsonAIServiceClient) AskJsonAI(ctx context.Context, in *AskJsonAI_Reque
 	return out, nil
 }
 
+func (c *jsonAIServiceClient) AskJsonAIStream(ctx context.Context, in *AskJsonAI_Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AskJsonAIStream_Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JsonAIService_ServiceDesc.Streams[0], JsonAIService_AskJsonAIStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AskJsonAI_Request, AskJsonAIStream_Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JsonAIService_AskJsonAIStreamClient = grpc.ServerStreamingClient[AskJsonAIStream_Event]
+
+func (c *jsonAIServiceClient) GetChatFile(ctx context.Context, in *GetChatFile_Request, opts ...grpc.CallOption) (*GetChatFile_Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChatFile_Response)
+	err := c.cc.Invoke(ctx, JsonAIService_GetChatFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jsonAIServiceClient) GetUploadStatus(ctx context.Context, in *GetUploadStatus_Request, opts ...grpc.CallOption) (*GetUploadStatus_Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUploadStatus_Response)
+	err := c.cc.Invoke(ctx, JsonAIService_GetUploadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jsonAIServiceClient) GetIngestionReport(ctx context.Context, in *GetIngestionReport_Request, opts ...grpc.CallOption) (*GetIngestionReport_Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIngestionReport_Response)
+	err := c.cc.Invoke(ctx, JsonAIService_GetIngestionReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jsonAIServiceClient) GetDatasetProfile(ctx context.Context, in *GetDatasetProfile_Request, opts ...grpc.CallOption) (*GetDatasetProfile_Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDatasetProfile_Response)
+	err := c.cc.Invoke(ctx, JsonAIService_GetDatasetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // JsonAIServiceServer is the server API for JsonAIService service.
 // All implementations must embed UnimplementedJsonAIServiceServer
 // for forward compatibility.
@@ -104,6 +175,13 @@ type JsonAIServiceServer interface {
 	ListChats(context.Context, *ListChats_Request) (*ListChats_Response, error)
 	GetChat(context.Context, *GetChat_Request) (*GetChat_Response, error)
 	AskJsonAI(context.Context, *AskJsonAI_Request) (*AskJsonAI_Response, error)
+	// Not exposed through the gateway, HTTP clients stream over server-sent events from
+	// POST /json-ai/user/{userID}/chat/{chatID}/stream
+	AskJsonAIStream(*AskJsonAI_Request, grpc.ServerStreamingServer[AskJsonAIStream_Event]) error
+	GetChatFile(context.Context, *GetChatFile_Request) (*GetChatFile_Response, error)
+	GetUploadStatus(context.Context, *GetUploadStatus_Request) (*GetUploadStatus_Response, error)
+	GetIngestionReport(context.Context, *GetIngestionReport_Request) (*GetIngestionReport_Response, error)
+	GetDatasetProfile(context.Context, *GetDatasetProfile_Request) (*GetDatasetProfile_Response, error)
 	mustEmbedUnimplementedJsonAIServiceServer()
 }
 
@@ -129,6 +207,21 @@ func (UnimplementedJsonAIServiceServer) GetChat(context.Context, *GetChat_Reques
 func (UnimplementedJsonAIServiceServer) AskJsonAI(context.Context, *AskJsonAI_Request) (*AskJsonAI_Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AskJsonAI not implemented")
 }
+func (UnimplementedJsonAIServiceServer) AskJsonAIStream(*AskJsonAI_Request, grpc.ServerStreamingServer[AskJsonAIStream_Event]) error {
+	return status.Errorf(codes.Unimplemented, "method AskJsonAIStream not implemented")
+}
+func (UnimplementedJsonAIServiceServer) GetChatFile(context.Context, *GetChatFile_Request) (*GetChatFile_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChatFile not implemented")
+}
+func (UnimplementedJsonAIServiceServer) GetUploadStatus(context.Context, *GetUploadStatus_Request) (*GetUploadStatus_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadStatus not implemented")
+}
+func (UnimplementedJsonAIServiceServer) GetIngestionReport(context.Context, *GetIngestionReport_Request) (*GetIngestionReport_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIngestionReport not implemented")
+}
+func (UnimplementedJsonAIServiceServer) GetDatasetProfile(context.Context, *GetDatasetProfile_Request) (*GetDatasetProfile_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDatasetProfile not implemented")
+}
 func (UnimplementedJsonAIServiceServer) mustEmbedUnimplementedJsonAIServiceServer() {}
 func (UnimplementedJsonAIServiceServer) testEmbeddedByValue()                       {}
 
@@ -240,6 +333,89 @@ func _JsonAIService_AskJsonAI_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JsonAIService_AskJsonAIStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AskJsonAI_Request)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JsonAIServiceServer).AskJsonAIStream(m, &grpc.GenericServerStream[AskJsonAI_Request, AskJsonAIStream_Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JsonAIService_AskJsonAIStreamServer = grpc.ServerStreamingServer[AskJsonAIStream_Event]
+
+func _JsonAIService_GetChatFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChatFile_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JsonAIServiceServer).GetChatFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JsonAIService_GetChatFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JsonAIServiceServer).GetChatFile(ctx, req.(*GetChatFile_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JsonAIService_GetUploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadStatus_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JsonAIServiceServer).GetUploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JsonAIService_GetUploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JsonAIServiceServer).GetUploadStatus(ctx, req.(*GetUploadStatus_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JsonAIService_GetIngestionReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIngestionReport_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JsonAIServiceServer).GetIngestionReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JsonAIService_GetIngestionReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JsonAIServiceServer).GetIngestionReport(ctx, req.(*GetIngestionReport_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JsonAIService_GetDatasetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatasetProfile_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JsonAIServiceServer).GetDatasetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JsonAIService_GetDatasetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JsonAIServiceServer).GetDatasetProfile(ctx, req.(*GetDatasetProfile_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // JsonAIService_ServiceDesc is the grpc.ServiceDesc for JsonAIService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,7 +443,29 @@ var JsonAIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AskJsonAI",
 			Handler:    _JsonAIService_AskJsonAI_Handler,
 		},
+		{
+			MethodName: "GetChatFile",
+			Handler:    _JsonAIService_GetChatFile_Handler,
+		},
+		{
+			MethodName: "GetUploadStatus",
+			Handler:    _JsonAIService_GetUploadStatus_Handler,
+		},
+		{
+			MethodName: "GetIngestionReport",
+			Handler:    _JsonAIService_GetIngestionReport_Handler,
+		},
+		{
+			MethodName: "GetDatasetProfile",
+			Handler:    _JsonAIService_GetDatasetProfile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AskJsonAIStream",
+			Handler:       _JsonAIService_AskJsonAIStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "jai.proto",
 }
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	_ "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -95,11 +96,16 @@ type Chat struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChatID       string     `protobuf:"bytes,1,opt,name=chatID,proto3" json:"chatID,omitempty"`
-	UserID       string     `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
-	JsonName     string     `protobuf:"bytes,3,opt,name=jsonName,proto3" json:"jsonName,omitempty"`
-	MessageCount int32      `protobuf:"varint,4,opt,name=messageCount,proto3" json:"messageCount,omitempty"`
-	Messages     []*Message `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`
+	ChatID          string           `protobuf:"bytes,1,opt,name=chatID,proto3" json:"chatID,omitempty"`
+	UserID          string           `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	JsonName        string           `protobuf:"bytes,3,opt,name=jsonName,proto3" json:"jsonName,omitempty"`
+	MessageCount    int32            `protobuf:"varint,4,opt,name=messageCount,proto3" json:"messageCount,omitempty"`
+	Messages        []*Message       `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`
+	ExpiresAt       string           `protobuf:"bytes,6,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"` // Empty when the data is kept forever
+	Expired         bool             `protobuf:"varint,7,opt,name=expired,proto3" json:"expired,omitempty"`
+	IngestionReport *IngestionReport `protobuf:"bytes,8,opt,name=ingestionReport,proto3" json:"ingestionReport,omitempty"` // Only set for files loaded into DuckDB
+	Model           string           `protobuf:"bytes,9,opt,name=model,proto3" json:"model,omitempty"`                     // Model chosen for every stage of the chat, empty for the configured models
+	Models          *StageModels     `protobuf:"bytes,10,opt,name=models,proto3" json:"models,omitempty"`                  // Models chosen for single stages of the chat, they take precedence over model
 }
 
 func (x *Chat) Reset() {
@@ -167,30 +173,69 @@ func (x *Chat) GetMessages() []*Message {
 	return nil
 }
 
-type Message struct {
+func (x *Chat) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Chat) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *Chat) GetIngestionReport() *IngestionReport {
+	if x != nil {
+		return x.IngestionReport
+	}
+	return nil
+}
+
+func (x *Chat) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Chat) GetModels() *StageModels {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+// IngestionReport describes how much of an uploaded JSON file was loaded into DuckDB.
+type IngestionReport struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Role      string `protobuf:"bytes,1,opt,name=Role,proto3" json:"Role,omitempty"`
-	Message   string `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
-	CreatedAt string `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	TotalRecords   int32             `protobuf:"varint,1,opt,name=totalRecords,proto3" json:"totalRecords,omitempty"`
+	LoadedRecords  int32             `protobuf:"varint,2,opt,name=loadedRecords,proto3" json:"loadedRecords,omitempty"`
+	SkippedRecords int32             `protobuf:"varint,3,opt,name=skippedRecords,proto3" json:"skippedRecords,omitempty"`
+	Skipped        []*SkippedRecords `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	Tables         []*IngestedTable  `protobuf:"bytes,5,rep,name=tables,proto3" json:"tables,omitempty"`
+	RecordPath     string            `protobuf:"bytes,6,opt,name=recordPath,proto3" json:"recordPath,omitempty"` // JSON Pointer of the array the records were loaded from, empty for the whole document
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
+func (x *IngestionReport) Reset() {
+	*x = IngestionReport{}
 	mi := &file_objects_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Message) String() string {
+func (x *IngestionReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message) ProtoMessage() {}
+func (*IngestionReport) ProtoMessage() {}
 
-func (x *Message) ProtoReflect() protoreflect.Message {
+func (x *IngestionReport) ProtoReflect() protoreflect.Message {
 	mi := &file_objects_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -202,64 +247,1037 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
+// Deprecated: Use IngestionReport.ProtoReflect.Descriptor instead.
+func (*IngestionReport) Descriptor() ([]byte, []int) {
 	return file_objects_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Message) GetRole() string {
+func (x *IngestionReport) GetTotalRecords() int32 {
 	if x != nil {
-		return x.Role
+		return x.TotalRecords
+	}
+	return 0
+}
+
+func (x *IngestionReport) GetLoadedRecords() int32 {
+	if x != nil {
+		return x.LoadedRecords
+	}
+	return 0
+}
+
+func (x *IngestionReport) GetSkippedRecords() int32 {
+	if x != nil {
+		return x.SkippedRecords
+	}
+	return 0
+}
+
+func (x *IngestionReport) GetSkipped() []*SkippedRecords {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+func (x *IngestionReport) GetTables() []*IngestedTable {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+func (x *IngestionReport) GetRecordPath() string {
+	if x != nil {
+		return x.RecordPath
 	}
 	return ""
 }
 
-func (x *Message) GetMessage() string {
+type SkippedRecords struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason        string  `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Count         int32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	SampleIndexes []int32 `protobuf:"varint,3,rep,packed,name=sampleIndexes,proto3" json:"sampleIndexes,omitempty"` // Indexes of some of the skipped records in the JSON array
+}
+
+func (x *SkippedRecords) Reset() {
+	*x = SkippedRecords{}
+	mi := &file_objects_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkippedRecords) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkippedRecords) ProtoMessage() {}
+
+func (x *SkippedRecords) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[3]
 	if x != nil {
-		return x.Message
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkippedRecords.ProtoReflect.Descriptor instead.
+func (*SkippedRecords) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SkippedRecords) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *Message) GetCreatedAt() string {
+func (x *SkippedRecords) GetCount() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SkippedRecords) GetSampleIndexes() []int32 {
+	if x != nil {
+		return x.SampleIndexes
+	}
+	return nil
+}
+
+type IngestedTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Rows          int64             `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	Columns       []*IngestedColumn `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+	RenamedFields []*IngestedColumn `protobuf:"bytes,4,rep,name=renamedFields,proto3" json:"renamedFields,omitempty"` // Nested fields renamed because their keys only differ in case, named by their path
+}
+
+func (x *IngestedTable) Reset() {
+	*x = IngestedTable{}
+	mi := &file_objects_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestedTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestedTable) ProtoMessage() {}
+
+func (x *IngestedTable) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestedTable.ProtoReflect.Descriptor instead.
+func (*IngestedTable) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IngestedTable) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IngestedTable) GetRows() int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *IngestedTable) GetColumns() []*IngestedColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *IngestedTable) GetRenamedFields() []*IngestedColumn {
+	if x != nil {
+		return x.RenamedFields
+	}
+	return nil
+}
+
+type IngestedColumn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JsonKey string `protobuf:"bytes,2,opt,name=jsonKey,proto3" json:"jsonKey,omitempty"`
+	Type    string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *IngestedColumn) Reset() {
+	*x = IngestedColumn{}
+	mi := &file_objects_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestedColumn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestedColumn) ProtoMessage() {}
+
+func (x *IngestedColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestedColumn.ProtoReflect.Descriptor instead.
+func (*IngestedColumn) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IngestedColumn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IngestedColumn) GetJsonKey() string {
+	if x != nil {
+		return x.JsonKey
+	}
+	return ""
+}
+
+func (x *IngestedColumn) GetType() string {
+	if x != nil {
+		return x.Type
 	}
 	return ""
 }
 
+// ResultTable holds the rows of the query run to answer a question, in the column order of the query.
+type ResultTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Columns           []string              `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows              []*structpb.ListValue `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`                            // One value per column, nested values are JSON objects and arrays
+	OmittedRows       int64                 `protobuf:"varint,3,opt,name=omittedRows,proto3" json:"omittedRows,omitempty"`             // Rows the query returned beyond the row and size limits
+	OmittedRowsCapped bool                  `protobuf:"varint,4,opt,name=omittedRowsCapped,proto3" json:"omittedRowsCapped,omitempty"` // Only the first rows beyond the limits were counted, omittedRows is a lower bound
+	TruncatedCells    bool                  `protobuf:"varint,5,opt,name=truncatedCells,proto3" json:"truncatedCells,omitempty"`       // Values too long for the size limit were cut and end in "…"
+}
+
+func (x *ResultTable) Reset() {
+	*x = ResultTable{}
+	mi := &file_objects_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultTable) ProtoMessage() {}
+
+func (x *ResultTable) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultTable.ProtoReflect.Descriptor instead.
+func (*ResultTable) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResultTable) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ResultTable) GetRows() []*structpb.ListValue {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *ResultTable) GetOmittedRows() int64 {
+	if x != nil {
+		return x.OmittedRows
+	}
+	return 0
+}
+
+func (x *ResultTable) GetOmittedRowsCapped() bool {
+	if x != nil {
+		return x.OmittedRowsCapped
+	}
+	return false
+}
+
+func (x *ResultTable) GetTruncatedCells() bool {
+	if x != nil {
+		return x.TruncatedCells
+	}
+	return false
+}
+
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Role           string          `protobuf:"bytes,1,opt,name=Role,proto3" json:"Role,omitempty"`
+	Message        string          `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	CreatedAt      string          `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Models         *StageModels    `protobuf:"bytes,4,opt,name=models,proto3" json:"models,omitempty"`                 // Only set for assistant messages answering a question
+	Sql            string          `protobuf:"bytes,5,opt,name=sql,proto3" json:"sql,omitempty"`                       // Query the answer is based on, only set for answers about large files
+	ResultTable    *ResultTable    `protobuf:"bytes,6,opt,name=resultTable,proto3" json:"resultTable,omitempty"`       // Rows of that query as given to the model
+	FailedAttempts []*FailedQuery  `protobuf:"bytes,7,rep,name=failedAttempts,proto3" json:"failedAttempts,omitempty"` // Generated queries that were rejected or failed, in order
+	Timings        *MessageTimings `protobuf:"bytes,8,opt,name=timings,proto3" json:"timings,omitempty"`               // Only set for assistant messages answering a question
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_objects_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Message) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Message) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Message) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Message) GetModels() *StageModels {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+func (x *Message) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *Message) GetResultTable() *ResultTable {
+	if x != nil {
+		return x.ResultTable
+	}
+	return nil
+}
+
+func (x *Message) GetFailedAttempts() []*FailedQuery {
+	if x != nil {
+		return x.FailedAttempts
+	}
+	return nil
+}
+
+func (x *Message) GetTimings() *MessageTimings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
+type FailedQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sql   string `protobuf:"bytes,1,opt,name=sql,proto3" json:"sql,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *FailedQuery) Reset() {
+	*x = FailedQuery{}
+	mi := &file_objects_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedQuery) ProtoMessage() {}
+
+func (x *FailedQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedQuery.ProtoReflect.Descriptor instead.
+func (*FailedQuery) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FailedQuery) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *FailedQuery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// MessageTimings are the milliseconds spent in each stage of answering, stages that did not run are zero.
+type MessageTimings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValidationMs    int64 `protobuf:"varint,1,opt,name=validationMs,proto3" json:"validationMs,omitempty"`
+	SqlGenerationMs int64 `protobuf:"varint,2,opt,name=sqlGenerationMs,proto3" json:"sqlGenerationMs,omitempty"`
+	QueryMs         int64 `protobuf:"varint,3,opt,name=queryMs,proto3" json:"queryMs,omitempty"`
+	AnswerMs        int64 `protobuf:"varint,4,opt,name=answerMs,proto3" json:"answerMs,omitempty"`
+	TotalMs         int64 `protobuf:"varint,5,opt,name=totalMs,proto3" json:"totalMs,omitempty"`
+}
+
+func (x *MessageTimings) Reset() {
+	*x = MessageTimings{}
+	mi := &file_objects_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageTimings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageTimings) ProtoMessage() {}
+
+func (x *MessageTimings) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageTimings.ProtoReflect.Descriptor instead.
+func (*MessageTimings) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MessageTimings) GetValidationMs() int64 {
+	if x != nil {
+		return x.ValidationMs
+	}
+	return 0
+}
+
+func (x *MessageTimings) GetSqlGenerationMs() int64 {
+	if x != nil {
+		return x.SqlGenerationMs
+	}
+	return 0
+}
+
+func (x *MessageTimings) GetQueryMs() int64 {
+	if x != nil {
+		return x.QueryMs
+	}
+	return 0
+}
+
+func (x *MessageTimings) GetAnswerMs() int64 {
+	if x != nil {
+		return x.AnswerMs
+	}
+	return 0
+}
+
+func (x *MessageTimings) GetTotalMs() int64 {
+	if x != nil {
+		return x.TotalMs
+	}
+	return 0
+}
+
+// StageModels names the model used by each stage of answering a question, stages that did not run are empty.
+type StageModels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Validation string `protobuf:"bytes,1,opt,name=validation,proto3" json:"validation,omitempty"`
+	Sql        string `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`
+	Answer     string `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`
+}
+
+func (x *StageModels) Reset() {
+	*x = StageModels{}
+	mi := &file_objects_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageModels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageModels) ProtoMessage() {}
+
+func (x *StageModels) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageModels.ProtoReflect.Descriptor instead.
+func (*StageModels) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StageModels) GetValidation() string {
+	if x != nil {
+		return x.Validation
+	}
+	return ""
+}
+
+func (x *StageModels) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *StageModels) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+// DatasetProfile holds statistics of every column loaded into DuckDB.
+type DatasetProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tables []*TableProfile `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
+}
+
+func (x *DatasetProfile) Reset() {
+	*x = DatasetProfile{}
+	mi := &file_objects_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatasetProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatasetProfile) ProtoMessage() {}
+
+func (x *DatasetProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatasetProfile.ProtoReflect.Descriptor instead.
+func (*DatasetProfile) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DatasetProfile) GetTables() []*TableProfile {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+type TableProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Rows    int64            `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	Columns []*ColumnProfile `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+}
+
+func (x *TableProfile) Reset() {
+	*x = TableProfile{}
+	mi := &file_objects_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableProfile) ProtoMessage() {}
+
+func (x *TableProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableProfile.ProtoReflect.Descriptor instead.
+func (*TableProfile) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TableProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TableProfile) GetRows() int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *TableProfile) GetColumns() []*ColumnProfile {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+type ColumnProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string        `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	NullCount     int64         `protobuf:"varint,3,opt,name=nullCount,proto3" json:"nullCount,omitempty"`
+	DistinctCount int64         `protobuf:"varint,4,opt,name=distinctCount,proto3" json:"distinctCount,omitempty"`
+	Min           string        `protobuf:"bytes,5,opt,name=min,proto3" json:"min,omitempty"` // Empty for nested and boolean columns
+	Max           string        `protobuf:"bytes,6,opt,name=max,proto3" json:"max,omitempty"`
+	TopValues     []*ValueCount `protobuf:"bytes,7,rep,name=topValues,proto3" json:"topValues,omitempty"` // Most common values, only set for columns with few distinct values
+	Examples      []string      `protobuf:"bytes,8,rep,name=examples,proto3" json:"examples,omitempty"`   // Some values of columns with many distinct values
+}
+
+func (x *ColumnProfile) Reset() {
+	*x = ColumnProfile{}
+	mi := &file_objects_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnProfile) ProtoMessage() {}
+
+func (x *ColumnProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnProfile.ProtoReflect.Descriptor instead.
+func (*ColumnProfile) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ColumnProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ColumnProfile) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ColumnProfile) GetNullCount() int64 {
+	if x != nil {
+		return x.NullCount
+	}
+	return 0
+}
+
+func (x *ColumnProfile) GetDistinctCount() int64 {
+	if x != nil {
+		return x.DistinctCount
+	}
+	return 0
+}
+
+func (x *ColumnProfile) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *ColumnProfile) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+func (x *ColumnProfile) GetTopValues() []*ValueCount {
+	if x != nil {
+		return x.TopValues
+	}
+	return nil
+}
+
+func (x *ColumnProfile) GetExamples() []string {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+type ValueCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *ValueCount) Reset() {
+	*x = ValueCount{}
+	mi := &file_objects_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValueCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueCount) ProtoMessage() {}
+
+func (x *ValueCount) ProtoReflect() protoreflect.Message {
+	mi := &file_objects_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueCount.ProtoReflect.Descriptor instead.
+func (*ValueCount) Descriptor() ([]byte, []int) {
+	return file_objects_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ValueCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ValueCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 var File_objects_proto protoreflect.FileDescriptor
 
 var file_objects_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x66, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12,
-	0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
-	0x6d, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69,
-	0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22,
-	0xa2, 0x01, 0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74,
-	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44,
-	0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x73, 0x6f, 0x6e,
-	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f, 0x6e,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x73, 0x22, 0x55, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52,
-	0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a,
-	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x42, 0x1d, 0x5a, 0x1b, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x76, 0x69, 0x2f, 0x6a, 0x73,
-	0x6f, 0x6e, 0x61, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x66, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xde, 0x02,
+	0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x74, 0x49, 0x44, 0x12, 0x16,
+	0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x73, 0x6f, 0x6e, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x12, 0x40, 0x0a, 0x0f, 0x69, 0x6e,
+	0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x67, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x0f, 0x69, 0x6e, 0x67,
+	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x22, 0x82,
+	0x02, 0x0a, 0x0f, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6c,
+	0x6f, 0x61, 0x64, 0x65, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0e,
+	0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x6b,
+	0x69, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x52, 0x07, 0x73, 0x6b,
+	0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e,
+	0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x06, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x50, 0x61, 0x74,
+	0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x50,
+	0x61, 0x74, 0x68, 0x22, 0x64, 0x0a, 0x0e, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x49, 0x6e,
+	0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72,
+	0x6f, 0x77, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x67,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x52, 0x07, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x73, 0x12, 0x3b, 0x0a, 0x0d, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x64, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x52, 0x0d, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x73, 0x22, 0x52, 0x0a, 0x0e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6a, 0x73, 0x6f, 0x6e, 0x4b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6a, 0x73, 0x6f, 0x6e, 0x4b, 0x65,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xcf, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12,
+	0x2e, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12,
+	0x20, 0x0a, 0x0b, 0x6f, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x77, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6f, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x77,
+	0x73, 0x12, 0x2c, 0x0a, 0x11, 0x6f, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x77, 0x73,
+	0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6f, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x77, 0x73, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12,
+	0x26, 0x0a, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x65, 0x6c, 0x6c,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0xb6, 0x02, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x2a, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64,
+	0x65, 0x6c, 0x73, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x71, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x71, 0x6c, 0x12, 0x34, 0x0a,
+	0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x3a, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x0e, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12,
+	0x2f, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73,
+	0x22, 0x35, 0x0a, 0x0b, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x71, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x71,
+	0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xae, 0x01, 0x0a, 0x0e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x28,
+	0x0a, 0x0f, 0x73, 0x71, 0x6c, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x71, 0x6c, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x4d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x4d, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x4d, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x4d, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x73, 0x22, 0x57, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x67,
+	0x65, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x71, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x71, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x73,
+	0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73, 0x77, 0x65,
+	0x72, 0x22, 0x3d, 0x0a, 0x0e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73,
+	0x22, 0x66, 0x0a, 0x0c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x2e, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52,
+	0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x22, 0xec, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x24, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63,
+	0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x6f,
+	0x70, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x52, 0x09, 0x74, 0x6f, 0x70, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65,
+	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x65,
+	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0x38, 0x0a, 0x0a, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x1d, 0x5a, 0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x61, 0x76, 0x69, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x61, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -274,19 +1292,46 @@ func file_objects_proto_rawDescGZIP() []byte {
 	return file_objects_proto_rawDescData
 }
 
-var file_objects_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_objects_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_objects_proto_goTypes = []any{
-	(*User)(nil),    // 0: proto.User
-	(*Chat)(nil),    // 1: proto.Chat
-	(*Message)(nil), // 2: proto.Message
+	(*User)(nil),               // 0: proto.User
+	(*Chat)(nil),               // 1: proto.Chat
+	(*IngestionReport)(nil),    // 2: proto.IngestionReport
+	(*SkippedRecords)(nil),     // 3: proto.SkippedRecords
+	(*IngestedTable)(nil),      // 4: proto.IngestedTable
+	(*IngestedColumn)(nil),     // 5: proto.IngestedColumn
+	(*ResultTable)(nil),        // 6: proto.ResultTable
+	(*Message)(nil),            // 7: proto.Message
+	(*FailedQuery)(nil),        // 8: proto.FailedQuery
+	(*MessageTimings)(nil),     // 9: proto.MessageTimings
+	(*StageModels)(nil),        // 10: proto.StageModels
+	(*DatasetProfile)(nil),     // 11: proto.DatasetProfile
+	(*TableProfile)(nil),       // 12: proto.TableProfile
+	(*ColumnProfile)(nil),      // 13: proto.ColumnProfile
+	(*ValueCount)(nil),         // 14: proto.ValueCount
+	(*structpb.ListValue)(nil), // 15: google.protobuf.ListValue
 }
 var file_objects_proto_depIdxs = []int32{
-	2, // 0: proto.Chat.messages:type_name -> proto.Message
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	7,  // 0: proto.Chat.messages:type_name -> proto.Message
+	2,  // 1: proto.Chat.ingestionReport:type_name -> proto.IngestionReport
+	10, // 2: proto.Chat.models:type_name -> proto.StageModels
+	3,  // 3: proto.IngestionReport.skipped:type_name -> proto.SkippedRecords
+	4,  // 4: proto.IngestionReport.tables:type_name -> proto.IngestedTable
+	5,  // 5: proto.IngestedTable.columns:type_name -> proto.IngestedColumn
+	5,  // 6: proto.IngestedTable.renamedFields:type_name -> proto.IngestedColumn
+	15, // 7: proto.ResultTable.rows:type_name -> google.protobuf.ListValue
+	10, // 8: proto.Message.models:type_name -> proto.StageModels
+	6,  // 9: proto.Message.resultTable:type_name -> proto.ResultTable
+	8,  // 10: proto.Message.failedAttempts:type_name -> proto.FailedQuery
+	9,  // 11: proto.Message.timings:type_name -> proto.MessageTimings
+	12, // 12: proto.DatasetProfile.tables:type_name -> proto.TableProfile
+	13, // 13: proto.TableProfile.columns:type_name -> proto.ColumnProfile
+	14, // 14: proto.ColumnProfile.topValues:type_name -> proto.ValueCount
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_objects_proto_init() }
@@ -300,7 +1345,7 @@ func file_objects_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_objects_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
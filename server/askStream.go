@@ -0,0 +1,141 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Stages reported while answering a question
+const (
+	stageSummarizingHistory = "summarizing_history"
+	stageValidating         = "validating"
+	stageGeneratingSQL      = "generating_sql"
+	stageRunningQuery       = "running_query"
+	stageQueryFailed        = "query_failed"
+	stageAnswering          = "answering"
+)
+
+// answerStream sends the progress of answering a question to a streaming client. A nil stream ignores every event,
+// so AskJsonAI and AskJsonAIStream share the same code.
+type answerStream struct {
+	send func(event *proto.AskJsonAIStream_Event) error
+}
+
+// progress reports the stage the question reached. Failing to send it does not stop the answer, the request
+// context is cancelled when the client went away.
+func (a *answerStream) progress(stage string, attempt int, message string) {
+	if a == nil {
+		return
+	}
+
+	err := a.send(&proto.AskJsonAIStream_Event{Event: &proto.AskJsonAIStream_Event_Progress{
+		Progress: &proto.AskJsonAIStream_Progress{Stage: stage, Attempt: int32(attempt), Message: message},
+	}})
+	if err != nil {
+		log.Printf("Failed to send progress: %v", err)
+	}
+}
+
+func (a *answerStream) token(token string) error {
+	return a.send(&proto.AskJsonAIStream_Event{Event: &proto.AskJsonAIStream_Event_Token{Token: token}})
+}
+
+func (a *answerStream) done(response *proto.AskJsonAI_Response) error {
+	return a.send(&proto.AskJsonAIStream_Event{Event: &proto.AskJsonAIStream_Event_Done{Done: response}})
+}
+
+func (s Server) AskJsonAIStream(in *proto.AskJsonAI_Request, stream grpc.ServerStreamingServer[proto.AskJsonAIStream_Event]) error {
+	events := &answerStream{send: stream.Send}
+	response, err := s.askJsonAI(stream.Context(), in, events)
+	if err != nil {
+		return err
+	}
+	return events.done(response)
+}
+
+// handleAskJsonAIStream answers the question of an AskJsonAI request body as server-sent events, named after the
+// kind of AskJsonAIStream event they hold. Errors before the first event are returned as the HTTP status, later
+// errors as an error event.
+func (s Server) handleAskJsonAIStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logErrorAndRespond(w, "Error reading request", err, http.StatusBadRequest)
+		return
+	}
+
+	in := &proto.AskJsonAI_Request{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, in); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	in.UserID = mux.Vars(r)["userID"]
+	in.ChatID = mux.Vars(r)["chatID"]
+
+	started := false
+	events := &answerStream{send: func(event *proto.AskJsonAIStream_Event) error {
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		return writeServerSentEvent(w, flusher, serverSentEventName(event), event)
+	}}
+
+	response, err := s.askJsonAI(r.Context(), in, events)
+	if err != nil {
+		if !started {
+			http.Error(w, status.Convert(err).Message(), runtime.HTTPStatusFromCode(status.Code(err)))
+			return
+		}
+		if err := writeServerSentEvent(w, flusher, "error", status.Convert(err).Proto()); err != nil {
+			log.Printf("Failed to send error event: %v", err)
+		}
+		return
+	}
+
+	if err := events.done(response); err != nil {
+		log.Printf("Failed to send answer: %v", err)
+	}
+}
+
+func serverSentEventName(event *proto.AskJsonAIStream_Event) string {
+	switch event.Event.(type) {
+	case *proto.AskJsonAIStream_Event_Progress:
+		return "progress"
+	case *proto.AskJsonAIStream_Event_Token:
+		return "token"
+	default:
+		return "done"
+	}
+}
+
+// writeServerSentEvent writes the message as the JSON data of a single event and flushes it to the client.
+func writeServerSentEvent(w http.ResponseWriter, flusher http.Flusher, name string, message protobuf.Message) error {
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
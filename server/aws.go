@@ -1,13 +1,17 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"mime"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -16,26 +20,104 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-func (s Server) UploadToS3(filePath, key string) (string, error) {
-	// S3 Configurations
-	accessKeyID := s.AWS.AccessKey
-	secretAccessKey := s.AWS.SecretKey
-	awsRegion := s.AWS.Region
-	bucketName := s.AWS.BucketName
+// newS3Client builds the S3 client shared by the server. Static keys are used when configured, otherwise the
+// default AWS credential chain (env, shared config, instance role) is used. A custom endpoint allows S3-compatible
+// stores such as MinIO or LocalStack.
+func newS3Client(awsConfig AwsConfig) (*s3.Client, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(awsConfig.Region),
+	}
+	if awsConfig.AccessKey != "" && awsConfig.SecretKey != "" {
+		creds := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(awsConfig.AccessKey, awsConfig.SecretKey, ""))
+		opts = append(opts, config.WithCredentialsProvider(creds))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %v", err)
+	}
+
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if awsConfig.Endpoint != "" {
+			o.BaseEndpoint = aws.String(awsConfig.Endpoint)
+		}
+		o.UsePathStyle = awsConfig.UsePathStyle
+	}), nil
+}
+
+// objectURL returns the URL stored for an uploaded object. It mirrors the addressing style the client uses so
+// getBucketAndKeyFromS3URL can parse it back. The key is escaped, file names may contain '#', '?' or '%'.
+func (c AwsConfig) objectURL(key string) string {
+	key = escapeObjectKey(key)
+	if c.Endpoint != "" {
+		endpoint := strings.TrimRight(c.Endpoint, "/")
+		if c.UsePathStyle {
+			return fmt.Sprintf("%s/%s/%s", endpoint, c.BucketName, key)
+		}
+		scheme, host, found := strings.Cut(endpoint, "://")
+		if !found {
+			return fmt.Sprintf("https://%s.%s/%s", c.BucketName, endpoint, key)
+		}
+		return fmt.Sprintf("%s://%s.%s/%s", scheme, c.BucketName, host, key)
+	}
+
+	if c.UsePathStyle {
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", c.Region, c.BucketName, key)
+	}
+	if c.Region == "us-east-1" {
+		return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", c.BucketName, key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", c.BucketName, c.Region, key)
+}
+
+// endpointHost returns the host of the custom endpoint, which may be configured without a scheme.
+func (c AwsConfig) endpointHost() string {
+	endpoint := c.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
 
-	creds := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""))
+// escapeObjectKey escapes every segment of the key for use as a URL path.
+func escapeObjectKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// UploadToS3 uploads the file to the bucket, using a multipart upload for large files. When a master key is
+// configured the content is encrypted with the owner's data key before it leaves the server. progress may be nil.
+// The local file is removed once it is uploaded.
+func (s Server) UploadToS3(userID, filePath, key string, progress func(UploadProgress)) (string, error) {
+	// Set up file content type dynamically
+	ext := filepath.Ext(filePath)
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/json"
+	}
 
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(creds),
-	)
+	url, err := s.uploadFileToS3(userID, filePath, key, contentType, progress)
 	if err != nil {
-		log.Printf("Error loading AWS config: %v", err)
 		return "", err
 	}
 
-	client := s3.NewFromConfig(cfg)
+	// Remove the temporary file after successful upload
+	if err := os.Remove(filePath); err != nil {
+		log.Printf("Warning: unable to remove local file %q: %v", filePath, err)
+	}
+
+	log.Printf("Successfully uploaded %q to S3, URL: %s", filePath, url)
+	return url, nil
+}
 
+func (s Server) uploadFileToS3(userID, filePath, key, contentType string, progress func(UploadProgress)) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		log.Printf("Error opening file %q: %v", filePath, err)
@@ -51,82 +133,184 @@ func (s Server) UploadToS3(filePath, key string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("unable to get file info for %q, %v", filePath, err)
 	}
-	fileSize := fileInfo.Size()
 
-	// Set up file content type dynamically
-	ext := filepath.Ext(filePath)
-	contentType := mime.TypeByExtension(ext)
-	if contentType == "" {
-		contentType = "application/json"
+	object := s3Object{
+		key:         key,
+		body:        file,
+		size:        fileInfo.Size(),
+		contentType: contentType,
+		metadata: map[string]string{
+			"Content-Type": contentType,
+		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	if s.Keys.Enabled() {
+		plaintext, err := io.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read file %q, %v", filePath, err)
+		}
+		ciphertext, err := s.encryptForUser(userID, key, plaintext)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt %q: %v", filePath, err)
+		}
+
+		object.body = bytes.NewReader(ciphertext)
+		object.size = int64(len(ciphertext))
+		object.contentType = "application/octet-stream"
+		object.metadata[encryptionMetadataKey] = encryptionAlgorithm
+		object.metadata[ownerMetadataKey] = userID
+		object.metadata[plainSizeMetadataKey] = strconv.Itoa(len(plaintext))
+	}
 
 	// Upload the file to S3
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:        aws.String(bucketName),
-		Key:           aws.String(key),
-		Body:          file,
-		ContentLength: &fileSize,
-		ContentType:   aws.String(contentType),
-		Metadata: map[string]string{
-			"Content-Type": contentType,
-		},
-	})
+	err = s.putS3Object(context.Background(), object, progress)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload %q to S3: %v", filePath, err)
 	}
 
-	// Generate the file's S3 URL
-	var url string
-	if awsRegion == "us-east-1" {
-		url = fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucketName, key)
-	} else {
-		url = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucketName, awsRegion, key)
+	return s.AWS.objectURL(key), nil
+}
+
+func (s Server) DownloadFileFromS3(bucket, key string) (string, error) {
+	result, err := s.S3.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to get object from S3: %v", err)
 	}
+	defer result.Body.Close()
 
-	// Remove the temporary file after successful upload
-	if err := os.Remove(filePath); err != nil {
-		log.Printf("Warning: unable to remove local file %q: %v", filePath, err)
+	// Read the file content
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file content: %v", err)
 	}
 
-	log.Printf("Successfully uploaded %q to S3, URL: %s", filePath, url)
-	return url, nil
+	if result.Metadata[encryptionMetadataKey] != "" {
+		body, err = s.decryptForUser(result.Metadata, key, body)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt file content: %v", err)
+		}
+	}
+
+	return string(body), nil
 }
 
-func (s Server) DownloadFileFromS3(bucket, key string) (string, error) {
-	accessKeyID := s.AWS.AccessKey
-	secretAccessKey := s.AWS.SecretKey
-	awsRegion := s.AWS.Region
+// DownloadFileFromS3ToPath writes the object to filePath, decrypting it if needed.
+func (s Server) DownloadFileFromS3ToPath(ctx context.Context, bucket, key, filePath string) error {
+	result, err := s.S3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get object from S3: %v", err)
+	}
+	defer result.Body.Close()
 
-	creds := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""))
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer closeFile(dst)
 
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(creds),
-	)
+	if result.Metadata[encryptionMetadataKey] == "" {
+		if _, err := io.Copy(dst, result.Body); err != nil {
+			return fmt.Errorf("failed to write file content: %v", err)
+		}
+		return nil
+	}
+
+	// GCM authenticates the whole ciphertext, so encrypted objects are decrypted in memory
+	body, err := io.ReadAll(result.Body)
 	if err != nil {
-		log.Printf("Error loading AWS config: %v", err)
-		return "", err
+		return fmt.Errorf("failed to read file content: %v", err)
 	}
+	body, err = s.decryptForUser(result.Metadata, key, body)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file content: %v", err)
+	}
+	if _, err := dst.Write(body); err != nil {
+		return fmt.Errorf("failed to write file content: %v", err)
+	}
+	return nil
+}
 
-	client := s3.NewFromConfig(cfg)
+// objectInfo describes a stored object as the user uploaded it.
+type objectInfo struct {
+	Size      int64
+	Encrypted bool
+}
 
-	result, err := client.GetObject(context.TODO(), &s3.GetObjectInput{
+func (s Server) HeadS3Object(ctx context.Context, bucket, key string) (*objectInfo, error) {
+	result, err := s.S3.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return "", fmt.Errorf("unable to get object from S3: %v", err)
+		return nil, fmt.Errorf("unable to get object info from S3: %v", err)
+	}
+
+	info := &objectInfo{Size: aws.ToInt64(result.ContentLength)}
+	if result.Metadata[encryptionMetadataKey] != "" {
+		info.Encrypted = true
+		if size, err := strconv.ParseInt(result.Metadata[plainSizeMetadataKey], 10, 64); err == nil {
+			info.Size = size
+		}
+	}
+	return info, nil
+}
+
+// PresignS3Download returns a short-lived GET URL for the object.
+func (s Server) PresignS3Download(ctx context.Context, bucket, key, fileName string) (string, time.Time, error) {
+	presignClient := s3.NewPresignClient(s.S3)
+	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(fmt.Sprintf("attachment; filename=%q", fileName)),
+	}, s3.WithPresignExpires(s.AWS.PresignTTL))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to presign object: %v", err)
+	}
+	return request.URL, time.Now().Add(s.AWS.PresignTTL), nil
+}
+
+// StreamFileFromS3 copies the object to w, decrypting it first when it was stored encrypted.
+func (s Server) StreamFileFromS3(ctx context.Context, w io.Writer, bucket, key string) error {
+	result, err := s.S3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get object from S3: %v", err)
 	}
 	defer result.Body.Close()
 
-	// Read the file content
-	body, err := ioutil.ReadAll(result.Body)
+	if result.Metadata[encryptionMetadataKey] == "" {
+		_, err = io.Copy(w, result.Body)
+		return err
+	}
+
+	// GCM authenticates the whole object, so encrypted files are decrypted in memory before writing
+	ciphertext, err := io.ReadAll(result.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file content: %v", err)
+		return fmt.Errorf("failed to read file content: %v", err)
+	}
+	plaintext, err := s.decryptForUser(result.Metadata, key, ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file content: %v", err)
 	}
+	_, err = w.Write(plaintext)
+	return err
+}
 
-	return string(body), nil
+func (s Server) DeleteFromS3(ctx context.Context, bucket, key string) error {
+	_, err := s.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete object from S3: %v", err)
+	}
+	return nil
 }
@@ -0,0 +1,432 @@
+package server
+
+import (
+	"JsonAI/db"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Name the prebuilt database is attached under when answering questions
+	chatCatalog = "chat_data"
+
+	previewTable  = "json_preview"
+	previewLength = 5000
+
+	duckDBExtension = ".duckdb"
+)
+
+// duckDBCache keeps prebuilt chat databases on local disk so questions do not have to download them from S3.
+// The least recently used files are evicted once the cache grows over maxBytes; files of chats that are in use
+// are never evicted.
+type duckDBCache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	chats map[string]*cachedChat
+}
+
+type cachedChat struct {
+	mu   sync.Mutex // Held while the chat's database is downloaded or built
+	refs int
+}
+
+func newDuckDBCache(dir string, maxBytes int64) (*duckDBCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create DuckDB cache dir: %v", err)
+	}
+
+	// Remove files left over by builds that were interrupted
+	tmpFiles, _ := filepath.Glob(filepath.Join(dir, "*.tmp*"))
+	for _, tmpFile := range tmpFiles {
+		if err := os.Remove(tmpFile); err != nil {
+			log.Printf("Failed to remove %q: %v", tmpFile, err)
+		}
+	}
+
+	return &duckDBCache{dir: dir, maxBytes: maxBytes, chats: make(map[string]*cachedChat)}, nil
+}
+
+func (c *duckDBCache) path(chatID string) string {
+	return filepath.Join(c.dir, chatID+duckDBExtension)
+}
+
+// tempPath returns a path in the cache dir, so moving the finished file into place is an atomic rename.
+func (c *duckDBCache) tempPath(chatID string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s.tmp", chatID, uuid.New().String()))
+}
+
+// acquire marks the chat as in use and returns it locked. release must be called when the chat's database is
+// no longer used.
+func (c *duckDBCache) acquire(chatID string) *cachedChat {
+	c.mu.Lock()
+	chat, ok := c.chats[chatID]
+	if !ok {
+		chat = &cachedChat{}
+		c.chats[chatID] = chat
+	}
+	chat.refs++
+	c.mu.Unlock()
+
+	chat.mu.Lock()
+	return chat
+}
+
+func (c *duckDBCache) release(chatID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chat, ok := c.chats[chatID]
+	if !ok {
+		return
+	}
+	chat.refs--
+	if chat.refs <= 0 {
+		delete(c.chats, chatID)
+	}
+}
+
+// get returns the cached database of the chat and marks it as recently used.
+func (c *duckDBCache) get(chatID string) (string, bool) {
+	path := c.path(chatID)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		log.Printf("Failed to touch %q: %v", path, err)
+	}
+	return path, true
+}
+
+// put moves a finished database into the cache and evicts old entries if the cache is over its size limit.
+func (c *duckDBCache) put(chatID, tmpPath string) (string, error) {
+	path := c.path(chatID)
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("failed to move database into cache: %v", err)
+	}
+
+	c.evict()
+	return path, nil
+}
+
+func (c *duckDBCache) remove(chatID string) {
+	if err := os.Remove(c.path(chatID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove cached database of chat %s: %v", chatID, err)
+	}
+}
+
+func (c *duckDBCache) evict() {
+	if c.maxBytes <= 0 {
+		return
+	}
+
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*"+duckDBExtension))
+	if err != nil {
+		log.Printf("Failed to list DuckDB cache: %v", err)
+		return
+	}
+
+	type cacheFile struct {
+		path    string
+		chatID  string
+		size    int64
+		modTime time.Time
+	}
+
+	var total int64
+	files := make([]cacheFile, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		chatID := strings.TrimSuffix(filepath.Base(path), duckDBExtension)
+		files = append(files, cacheFile{path: path, chatID: chatID, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, file := range files {
+		if total <= c.maxBytes {
+			return
+		}
+		if _, inUse := c.chats[file.chatID]; inUse {
+			continue
+		}
+
+		if err := os.Remove(file.path); err != nil {
+			log.Printf("Failed to evict %q: %v", file.path, err)
+			continue
+		}
+		total -= file.size
+	}
+}
+
+// chatDuckDB is an in-memory DuckDB with the chat's prebuilt database attached read-only. It is sandboxed for
+// running generated queries.
+type chatDuckDB struct {
+	*sql.DB
+	Schema  *tableSchema
+	Preview string
+
+	release func()
+}
+
+func (c *chatDuckDB) Close() error {
+	defer c.release()
+	return c.DB.Close()
+}
+
+// openChatDuckDB attaches the chat's prebuilt database, fetching it from S3 or building it from the uploaded
+// file first if it is not in the local cache.
+func (s Server) openChatDuckDB(ctx context.Context, jChat *db.JaiChat) (*chatDuckDB, error) {
+	chatID := jChat.UUID.ID
+	chat := s.DuckDBCache.acquire(chatID)
+	release := func() { s.DuckDBCache.release(chatID) }
+
+	path, err := s.ensureChatDuckDB(ctx, jChat)
+	chat.mu.Unlock()
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	duckDB, err := sql.Open("duckdb", "")
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to open DuckDB: %v", err)
+	}
+	// ATTACH and USE apply to the connection, keep a single one
+	duckDB.SetMaxOpenConns(1)
+
+	chatDB := &chatDuckDB{DB: duckDB, release: release}
+	err = attachChatDuckDB(duckDB, path)
+	if err == nil {
+		chatDB.Schema, err = loadTableSchema(duckDB)
+	}
+	if err == nil {
+		err = duckDB.QueryRow(fmt.Sprintf("SELECT preview FROM %s;", previewTable)).Scan(&chatDB.Preview)
+	}
+	if err == nil {
+		err = sandboxDuckDB(duckDB, s.DuckDBMemoryLimit, s.DuckDBThreads)
+	}
+	if err != nil {
+		if cerr := chatDB.Close(); cerr != nil {
+			log.Printf("Failed to close DuckDB: %s", cerr)
+		}
+		return nil, err
+	}
+	return chatDB, nil
+}
+
+// queryGenerated validates a query written by the model and runs it. Queries that are not a single SELECT over
+// the chat's tables are rejected without running them.
+func (c *chatDuckDB) queryGenerated(ctx context.Context, query string, limits queryLimits) (*queryResult, error) {
+	tables := make(map[string]bool)
+	for _, table := range c.Schema.tables() {
+		tables[table.Name] = true
+	}
+
+	if err := validateSQL(c.DB, query, tables); err != nil {
+		return nil, fmt.Errorf("query rejected: %v", err)
+	}
+	return queryDuckDB(ctx, c.DB, query, limits)
+}
+
+func attachChatDuckDB(duckDB *sql.DB, path string) error {
+	_, err := duckDB.Exec(fmt.Sprintf("ATTACH '%s' AS %s (READ_ONLY);", strings.ReplaceAll(path, "'", "''"), chatCatalog))
+	if err != nil {
+		return fmt.Errorf("failed to attach chat database: %v", err)
+	}
+
+	_, err = duckDB.Exec(fmt.Sprintf("USE %s;", chatCatalog))
+	if err != nil {
+		return fmt.Errorf("failed to use chat database: %v", err)
+	}
+	return nil
+}
+
+// ensureChatDuckDB returns the local path of the chat's database. The caller must hold the chat's cache lock.
+func (s Server) ensureChatDuckDB(ctx context.Context, jChat *db.JaiChat) (string, error) {
+	chatID := jChat.UUID.ID
+	if path, ok := s.DuckDBCache.get(chatID); ok {
+		return path, nil
+	}
+
+	tmpPath := s.DuckDBCache.tempPath(chatID)
+	defer removeDuckDBFiles(tmpPath)
+
+	if jChat.DuckDBLocation != "" {
+		bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(jChat.DuckDBLocation)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse S3 URL: %v", err)
+		}
+
+		err = s.DownloadFileFromS3ToPath(ctx, bucket, key, tmpPath)
+		if err == nil {
+			return s.DuckDBCache.put(chatID, tmpPath)
+		}
+		// The database can always be rebuilt from the uploaded file
+		log.Printf("Failed to download database of chat %s, rebuilding it: %v", chatID, err)
+	}
+
+	bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(jChat.FileLocation)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse S3 URL: %v", err)
+	}
+
+	jsonContent, err := s.DownloadFileFromS3(bucket, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the file: %v", err)
+	}
+
+	if err := s.buildAndStoreChatDuckDB(jChat, []byte(jsonContent), tmpPath); err != nil {
+		return "", err
+	}
+	return s.DuckDBCache.put(chatID, tmpPath)
+}
+
+// prepareChatDuckDB builds the chat's database right after the upload so the first question does not have to.
+func (s Server) prepareChatDuckDB(jChat *db.JaiChat, jsonContent []byte) error {
+	chatID := jChat.UUID.ID
+	chat := s.DuckDBCache.acquire(chatID)
+	defer s.DuckDBCache.release(chatID)
+	defer chat.mu.Unlock()
+
+	tmpPath := s.DuckDBCache.tempPath(chatID)
+	defer removeDuckDBFiles(tmpPath)
+
+	if err := s.buildAndStoreChatDuckDB(jChat, jsonContent, tmpPath); err != nil {
+		return err
+	}
+
+	_, err := s.DuckDBCache.put(chatID, tmpPath)
+	return err
+}
+
+// buildAndStoreChatDuckDB builds the chat's database at path and uploads it next to the uploaded file. The
+// ingestion report, dataset profile and schema context are saved with the chat.
+func (s Server) buildAndStoreChatDuckDB(jChat *db.JaiChat, jsonContent []byte, path string) error {
+	start := time.Now()
+	built, err := buildChatDuckDB(path, jsonContent, jChat.IngestMode, jChat.RecordPath)
+	if err != nil {
+		return err
+	}
+	log.Printf("Built database of chat %s in %s", jChat.UUID.ID, time.Since(start))
+
+	_, key, err := s.AWS.getBucketAndKeyFromS3URL(jChat.FileLocation)
+	if err != nil {
+		return fmt.Errorf("failed to parse S3 URL: %v", err)
+	}
+
+	location, err := s.uploadFileToS3(jChat.UserID, path, key+duckDBExtension, "application/octet-stream", nil)
+	if err != nil {
+		return fmt.Errorf("failed to upload database: %v", err)
+	}
+
+	reportJSON, err := json.Marshal(built.Report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingestion report: %v", err)
+	}
+
+	profileJSON, err := json.Marshal(built.Profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dataset profile: %v", err)
+	}
+
+	schemaContextJSON, err := json.Marshal(built.SchemaContext)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema context: %v", err)
+	}
+
+	if err := db.SetChatDuckDB(s.DB, jChat.UUID.ID, location, string(reportJSON), string(profileJSON), string(schemaContextJSON)); err != nil {
+		return fmt.Errorf("failed to save database location: %v", err)
+	}
+	jChat.DuckDBLocation = location
+	jChat.IngestionReport = string(reportJSON)
+	jChat.DatasetProfile = string(profileJSON)
+	jChat.SchemaContext = string(schemaContextJSON)
+	return nil
+}
+
+// builtChatDuckDB is what is learned about the data while building a chat's database.
+type builtChatDuckDB struct {
+	Report        *IngestionReport
+	Profile       *DatasetProfile
+	SchemaContext *SchemaContext
+}
+
+// buildChatDuckDB loads the JSON into a new DuckDB database file, profiles the loaded columns and builds the
+// schema context of the prompts.
+func buildChatDuckDB(path string, jsonContent []byte, ingestMode, recordPath string) (*builtChatDuckDB, error) {
+	var jsonData interface{}
+	if err := json.Unmarshal(jsonContent, &jsonData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %v", err)
+	}
+
+	duckDB, err := sql.Open("duckdb", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %v", err)
+	}
+	defer func(duckDB *sql.DB) {
+		err := duckDB.Close()
+		if err != nil {
+			log.Printf("Failed to close DuckDB: %s", err)
+		}
+	}(duckDB)
+
+	built := &builtChatDuckDB{}
+	built.Report, err = LoadJSONIntoDuckDB(duckDB, jsonData, ingestMode, recordPath)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loadTableSchema(duckDB)
+	if err != nil {
+		return nil, err
+	}
+	built.Profile = profileDataset(duckDB, schema)
+
+	jsonPreview := getJSONPreview(string(jsonContent), previewLength)
+	_, err = duckDB.Exec(fmt.Sprintf("CREATE TABLE %s AS SELECT ? AS preview;", previewTable), jsonPreview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store JSON preview: %v", err)
+	}
+
+	built.SchemaContext, err = buildSchemaContext(context.Background(), duckDB, schema, built.Report, built.Profile, jsonPreview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schema context: %v", err)
+	}
+
+	// Write everything into the database file so it can be uploaded on its own
+	_, err = duckDB.Exec("CHECKPOINT;")
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint DuckDB: %v", err)
+	}
+	return built, nil
+}
+
+func removeDuckDBFiles(path string) {
+	for _, file := range []string{path, path + ".wal"} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove %q: %v", file, err)
+		}
+	}
+}
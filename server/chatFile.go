@@ -0,0 +1,138 @@
+package server
+
+import (
+	"JsonAI/db"
+	"JsonAI/proto"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (s Server) GetChatFile(ctx context.Context, in *proto.GetChatFile_Request) (*proto.GetChatFile_Response, error) {
+	if in.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserID is required")
+	}
+
+	if in.ChatID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ChatID is required")
+	}
+
+	jChat, err := s.getOwnedChat(in.UserID, in.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if jChat.IsExpired(time.Now()) {
+		return nil, status.Error(codes.FailedPrecondition, datasetExpiredResponse)
+	}
+
+	bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(jChat.FileLocation)
+	if err != nil {
+		log.Printf("Failed to parse S3 URL: %s", err)
+		return nil, status.Error(codes.Internal, "Failed to parse S3 URL")
+	}
+
+	info, err := s.HeadS3Object(ctx, bucket, key)
+	if err != nil {
+		log.Printf("Failed to get file info: %s", err)
+		return nil, status.Error(codes.Internal, "Failed to retrieve the file")
+	}
+
+	// Encrypted objects can only be read through the server since S3 only has the ciphertext
+	if s.AWS.Presign && !info.Encrypted {
+		url, expiresAt, err := s.PresignS3Download(ctx, bucket, key, jChat.JSON)
+		if err != nil {
+			log.Printf("Failed to presign file download: %s", err)
+			return nil, status.Error(codes.Internal, "Failed to retrieve the file")
+		}
+
+		return &proto.GetChatFile_Response{
+			FileName:    jChat.JSON,
+			Size:        info.Size,
+			DownloadURL: url,
+			ExpiresAt:   expiresAt.Format(time.RFC3339),
+			Presigned:   true,
+		}, nil
+	}
+
+	return &proto.GetChatFile_Response{
+		FileName:    jChat.JSON,
+		Size:        info.Size,
+		DownloadURL: fmt.Sprintf("/json-ai/user/%s/chat/%s/file/content", in.UserID, in.ChatID),
+	}, nil
+}
+
+func (s Server) handleChatFileDownload(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	chatID := mux.Vars(r)["chatID"]
+
+	jChat, err := s.getOwnedChat(userID, chatID)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, status.Convert(err).Message(), http.StatusNotFound)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if jChat.IsExpired(time.Now()) {
+		http.Error(w, datasetExpiredResponse, http.StatusGone)
+		return
+	}
+
+	bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(jChat.FileLocation)
+	if err != nil {
+		logErrorAndRespond(w, "Failed to parse S3 URL", err, http.StatusInternalServerError)
+		return
+	}
+
+	info, err := s.HeadS3Object(r.Context(), bucket, key)
+	if err != nil {
+		logErrorAndRespond(w, "Failed to retrieve the file", err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", jChat.JSON))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	if err := s.StreamFileFromS3(r.Context(), w, bucket, key); err != nil {
+		// Headers are already sent, all we can do is log and cut the response short
+		log.Printf("Failed to stream file for chat %s: %v", chatID, err)
+	}
+}
+
+// getOwnedChat returns the chat if it exists and belongs to the user. Chats of other users are reported as not found.
+func (s Server) getOwnedChat(userID, chatID string) (*db.JaiChat, error) {
+	jChat, _, err := s.getOwnedChatWithMessages(userID, chatID)
+	return jChat, err
+}
+
+// getOwnedChatWithMessages is getOwnedChat also returning the messages of the chat.
+func (s Server) getOwnedChatWithMessages(userID, chatID string) (*db.JaiChat, []*db.ChatMessages, error) {
+	jChat, messages, err := db.GetChatByID(s.DB, chatID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, status.Error(codes.NotFound, "Chat not found")
+		}
+		log.Printf("Failed to retrieve chat: %s", err)
+		return nil, nil, status.Error(codes.Internal, "Failed to retrieve chat")
+	}
+
+	if jChat.UserID != userID {
+		return nil, nil, status.Error(codes.NotFound, "Chat not found")
+	}
+
+	return jChat, messages, nil
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"JsonAI/db"
+	"context"
+	"fmt"
+	"github.com/sashabaranov/go-openai"
+	"log"
+	"strings"
+)
+
+const (
+	// Messages longer than this are shortened in the history, the full answer rarely matters for a follow-up
+	maxHistoryMessageLen = 1500
+
+	// History token budget when JAI_HISTORY_MAX_TOKENS is unset or 0
+	defaultHistoryMaxTokens = 1500
+)
+
+// chatHistory is the conversation before the current question: a summary of the older turns and the recent
+// messages as they were sent.
+type chatHistory struct {
+	Summary  string
+	Messages []*db.ChatMessages
+}
+
+// loadChatHistory returns the conversation of the chat within the history token budget. When the messages not
+// yet summarized exceed the budget, the older ones are summarized with model and the summary is stored with the
+// chat, keeping the newest messages within half of the budget. A budget of 0 uses defaultHistoryMaxTokens.
+func (s Server) loadChatHistory(ctx context.Context, model string, jChat *db.JaiChat, messages []*db.ChatMessages, stream *answerStream) *chatHistory {
+	// The greeting sent at upload is not part of the conversation
+	start := len(messages)
+	for i, message := range messages {
+		if message.Role == openai.ChatMessageRoleUser {
+			start = i
+			break
+		}
+	}
+
+	var pending []*db.ChatMessages
+	for _, message := range messages[start:] {
+		if message.ID > jChat.HistorySummaryUntil {
+			pending = append(pending, message)
+		}
+	}
+
+	maxTokens := s.HistoryMaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultHistoryMaxTokens
+	}
+
+	history := &chatHistory{Summary: jChat.HistorySummary}
+	if historyTokens(pending) <= maxTokens {
+		history.Messages = pending
+		return history
+	}
+
+	recent := recentMessages(pending, maxTokens/2)
+	older := pending[:len(pending)-len(recent)]
+
+	stream.progress(stageSummarizingHistory, 0, "")
+	summary, err := s.summarizeHistory(ctx, model, jChat.HistorySummary, older)
+	if err != nil {
+		// Answer with the turns that fit instead, summarizing is tried again on the next question
+		log.Printf("Failed to summarize history of chat %s: %v", jChat.UUID.ID, err)
+		history.Messages = recentMessages(pending, maxTokens)
+		return history
+	}
+
+	until := older[len(older)-1].ID
+	if err := db.SetChatHistorySummary(s.DB, jChat.UUID.ID, summary, until); err != nil {
+		log.Printf("Failed to save history summary of chat %s: %v", jChat.UUID.ID, err)
+	}
+	jChat.HistorySummary = summary
+	jChat.HistorySummaryUntil = until
+
+	history.Summary = summary
+	history.Messages = recent
+	return history
+}
+
+// recentMessages returns the newest messages whose history takes at most maxTokens, starting with a question so
+// an answer is never kept without it.
+func recentMessages(messages []*db.ChatMessages, maxTokens int) []*db.ChatMessages {
+	tokens := 0
+	start := len(messages)
+	for start > 0 {
+		tokens += estimateTokenCount(historyMessage(messages[start-1]))
+		if tokens > maxTokens {
+			break
+		}
+		start--
+	}
+	for start < len(messages) && messages[start].Role != openai.ChatMessageRoleUser {
+		start++
+	}
+	return messages[start:]
+}
+
+func historyTokens(messages []*db.ChatMessages) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += estimateTokenCount(historyMessage(message))
+	}
+	return tokens
+}
+
+// historyMessage renders a message for the prompts, answers include the SQL they were based on.
+func historyMessage(message *db.ChatMessages) string {
+	text := message.Message
+	if runes := []rune(text); len(runes) > maxHistoryMessageLen {
+		text = string(runes[:maxHistoryMessageLen]) + "..."
+	}
+
+	if message.Role == openai.ChatMessageRoleUser {
+		return fmt.Sprintf("User: %s\n", text)
+	}
+	if message.SQL != "" {
+		return fmt.Sprintf("Assistant (answered with the query: %s): %s\n", message.SQL, text)
+	}
+	return fmt.Sprintf("Assistant: %s\n", text)
+}
+
+// summarizeHistory merges the messages into the summary of the earlier conversation.
+func (s Server) summarizeHistory(ctx context.Context, model, summary string, messages []*db.ChatMessages) (string, error) {
+	var conversation strings.Builder
+	for _, message := range messages {
+		conversation.WriteString(historyMessage(message))
+	}
+
+	previous := ""
+	if summary != "" {
+		previous = fmt.Sprintf("Summary of the conversation before these messages:\n%s\n\n", summary)
+	}
+
+	summaryMessages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: `You are an AI assistant that summarizes a conversation between a user and an assistant answering questions about the user's JSON data. The summary is used to understand follow-up questions, so keep what they could refer to: the questions asked, the fields, filters, time ranges and groupings used, the key numbers of the answers and the SQL queries run.`},
+		{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf(`%sMessages:
+%s
+Write a concise summary of the whole conversation above in at most 200 words. Only output the summary.`, previous, conversation.String())},
+	}
+
+	response, err := s.chat(ctx, model, &summaryMessages)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize the conversation: %v", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// describe renders the history for the prompts, empty when there is no earlier conversation.
+func (h *chatHistory) describe() string {
+	if h == nil || (h.Summary == "" && len(h.Messages) == 0) {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString("\nThe question is part of a conversation and may refer to earlier questions and answers (e.g. \"and what about last year?\"). Interpret it in the context of the conversation so far:\n")
+	if h.Summary != "" {
+		result.WriteString(fmt.Sprintf("Summary of the earlier conversation: %s\n", h.Summary))
+	}
+	for _, message := range h.Messages {
+		result.WriteString(historyMessage(message))
+	}
+	return result.String()
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"JsonAI/db"
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestLoadChatHistoryWithUnsetBudget(t *testing.T) {
+	// Without rules every call fails, the history must fit without summarizing
+	model := &scriptedModel{}
+	s := Server{LLM: model}
+
+	var messages []*db.ChatMessages
+	for i := 0; i < 4; i++ {
+		messages = append(messages,
+			&db.ChatMessages{Role: openai.ChatMessageRoleUser, Message: "How many orders were placed last week?"},
+			&db.ChatMessages{Role: openai.ChatMessageRoleAssistant, Message: "There were 42 orders last week.", SQL: "SELECT count(*) FROM json_data"},
+		)
+	}
+	for i, message := range messages {
+		message.ID = uint(i + 1)
+	}
+
+	history := s.loadChatHistory(context.Background(), "validation-model", &db.JaiChat{}, messages, nil)
+	if calls := len(model.Calls()); calls != 0 {
+		t.Errorf("Got %d model calls, want the history kept without summarizing", calls)
+	}
+	if len(history.Messages) != len(messages) || history.Summary != "" {
+		t.Errorf("Got %d messages and summary %q, want all %d messages", len(history.Messages), history.Summary, len(messages))
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	llmProviderOpenAI = "openai"
+	llmProviderFake   = "fake"
+)
+
+// ChatModel is a language model provider answering a conversation with the named model. Every prompt of
+// jsonAI.go is sent through it.
+type ChatModel interface {
+	Chat(ctx context.Context, model string, messages []openai.ChatCompletionMessage) (string, error)
+
+	// ChatStream is Chat with every piece of the response passed to onToken as it is generated. An error returned
+	// by onToken stops the response.
+	ChatStream(ctx context.Context, model string, messages []openai.ChatCompletionMessage, onToken func(token string) error) (string, error)
+}
+
+// newChatModel returns the configured provider: openai for the OpenAI API or a compatible server at baseURL, fake
+// for the scripted model read from scriptPath.
+func newChatModel(provider, apiKey, baseURL, scriptPath string) (ChatModel, error) {
+	switch provider {
+	case llmProviderOpenAI:
+		return newOpenAIModel(apiKey, baseURL), nil
+	case llmProviderFake:
+		return loadScriptedModel(scriptPath)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", provider)
+	}
+}
+
+// chat sends the conversation to the model and appends its response, so follow-up prompts can refer to it.
+func (s Server) chat(ctx context.Context, model string, messages *[]openai.ChatCompletionMessage) (string, error) {
+	response, err := s.LLM.Chat(ctx, model, *messages)
+	if err != nil {
+		return "", err
+	}
+	*messages = append(*messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: response})
+
+	return response, nil
+}
+
+// chatStream is chat with the response sent to stream as it is generated. Without a stream it is the same as chat.
+func (s Server) chatStream(ctx context.Context, model string, messages *[]openai.ChatCompletionMessage, stream *answerStream) (string, error) {
+	if stream == nil {
+		return s.chat(ctx, model, messages)
+	}
+
+	response, err := s.LLM.ChatStream(ctx, model, *messages, stream.token)
+	if err != nil {
+		return "", err
+	}
+	*messages = append(*messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: response})
+
+	return response, nil
+}
+
+// scriptRule answers every conversation whose last message contains Contains with Response. Rules with a Model
+// only answer that model.
+type scriptRule struct {
+	Contains string `json:"contains"`
+	Model    string `json:"model,omitempty"`
+	Response string `json:"response"`
+}
+
+// scriptedModel is a deterministic fake for testing the pipeline without calling a real model. The rules are
+// checked in order against the last message and the first match is returned.
+type scriptedModel struct {
+	Rules   []scriptRule `json:"rules"`
+	Default *string      `json:"default"` // Response when no rule matches, an error is returned when unset
+
+	mu    sync.Mutex
+	calls [][]openai.ChatCompletionMessage
+}
+
+func loadScriptedModel(path string) (*scriptedModel, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the fake LLM provider needs a script")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LLM script: %v", err)
+	}
+
+	var model scriptedModel
+	if err := json.Unmarshal(content, &model); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM script: %v", err)
+	}
+	return &model, nil
+}
+
+func (m *scriptedModel) Chat(ctx context.Context, model string, messages []openai.ChatCompletionMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, append([]openai.ChatCompletionMessage{}, messages...))
+	m.mu.Unlock()
+
+	last := ""
+	if len(messages) > 0 {
+		last = messages[len(messages)-1].Content
+	}
+	for _, rule := range m.Rules {
+		if strings.Contains(last, rule.Contains) && (rule.Model == "" || rule.Model == model) {
+			return rule.Response, nil
+		}
+	}
+
+	if m.Default != nil {
+		return *m.Default, nil
+	}
+	return "", fmt.Errorf("no scripted response for %q", truncateProfileValue(last))
+}
+
+// ChatStream sends the scripted response word by word.
+func (m *scriptedModel) ChatStream(ctx context.Context, model string, messages []openai.ChatCompletionMessage, onToken func(token string) error) (string, error) {
+	response, err := m.Chat(ctx, model, messages)
+	if err != nil {
+		return "", err
+	}
+
+	for _, token := range strings.SplitAfter(response, " ") {
+		if token == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := onToken(token); err != nil {
+			return "", err
+		}
+	}
+	return response, nil
+}
+
+// Calls returns the conversations the model was asked to answer, in order.
+func (m *scriptedModel) Calls() [][]openai.ChatCompletionMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]openai.ChatCompletionMessage{}, m.calls...)
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"strings"
+)
+
+const (
+	// Columns with at most this many distinct values list every value, so the model can tell a value is missing
+	lowCardinalityLimit = 20
+
+	exampleValuesLimit = 3
+	maxProfileValueLen = 80
+)
+
+// DatasetProfile holds statistics of every column loaded into DuckDB, so the model knows the values and ranges
+// of the data instead of guessing them.
+type DatasetProfile struct {
+	Tables []*TableProfile `json:"tables"`
+}
+
+type TableProfile struct {
+	Name    string           `json:"name"`
+	Rows    int64            `json:"rows"`
+	Columns []*ColumnProfile `json:"columns"`
+}
+
+type ColumnProfile struct {
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	NullCount     int64         `json:"nullCount"`
+	DistinctCount int64         `json:"distinctCount"`
+	Min           string        `json:"min,omitempty"`
+	Max           string        `json:"max,omitempty"`
+	TopValues     []*ValueCount `json:"topValues,omitempty"` // Only set for low-cardinality columns
+	Examples      []string      `json:"examples,omitempty"`
+}
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// profileDataset computes the profile of the columns of every table in the schema. The profile only helps the
+// model, tables and columns that fail to profile are left out instead of failing the load.
+func profileDataset(db *sql.DB, schema *tableSchema) *DatasetProfile {
+	profile := &DatasetProfile{}
+	for _, table := range schema.tables() {
+		tableProfile := &TableProfile{Name: table.Name}
+		err := db.QueryRow(fmt.Sprintf("SELECT count(*) FROM %s;", quoteIdentifier(table.Name))).Scan(&tableProfile.Rows)
+		if err != nil {
+			log.Printf("Failed to count rows of %s: %v", table.Name, err)
+			continue
+		}
+
+		types, err := columnTypes(db, table.Name)
+		if err != nil {
+			log.Printf("Failed to profile %s: %v", table.Name, err)
+			continue
+		}
+
+		for _, column := range table.Columns {
+			columnType, ok := types[column.Name]
+			if !ok {
+				log.Printf("Failed to profile %s.%s: no such column", table.Name, column.Name)
+				continue
+			}
+			columnProfile, err := profileColumn(db, table.Name, column.Name, columnType)
+			if err != nil {
+				log.Printf("Failed to profile %s.%s: %v", table.Name, column.Name, err)
+				continue
+			}
+			tableProfile.Columns = append(tableProfile.Columns, columnProfile)
+		}
+		profile.Tables = append(profile.Tables, tableProfile)
+	}
+	return profile
+}
+
+// columnTypes returns the type of every column of the table by name.
+func columnTypes(db *sql.DB, tableName string) (map[string]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info('%s');", strings.ReplaceAll(tableName, "'", "''")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table schema: %v", err)
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notnull, pk bool
+		var dfltValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notnull, &dfltValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan table schema: %v", err)
+		}
+		types[name] = colType
+	}
+	return types, rows.Err()
+}
+
+func profileColumn(db *sql.DB, tableName, columnName, columnType string) (*ColumnProfile, error) {
+	table := quoteIdentifier(tableName)
+	column := quoteIdentifier(columnName)
+	profile := &ColumnProfile{Name: columnName, Type: columnType}
+
+	// Min and max of nested values mean nothing to the model
+	minMax := fmt.Sprintf("min(%s)::VARCHAR, max(%s)::VARCHAR", column, column)
+	nested := strings.HasPrefix(columnType, typeStruct) || strings.HasSuffix(columnType, "[]") || columnType == typeJSON
+	if nested || columnType == typeBoolean {
+		minMax = "NULL, NULL"
+	}
+
+	var minValue, maxValue sql.NullString
+	err := db.QueryRow(fmt.Sprintf("SELECT count(*) - count(%s), count(DISTINCT %s), %s FROM %s;", column, column, minMax, table)).
+		Scan(&profile.NullCount, &profile.DistinctCount, &minValue, &maxValue)
+	if err != nil {
+		return nil, err
+	}
+	profile.Min = truncateProfileValue(minValue.String)
+	profile.Max = truncateProfileValue(maxValue.String)
+
+	if profile.DistinctCount <= lowCardinalityLimit && !nested {
+		rows, err := db.Query(fmt.Sprintf("SELECT %s::VARCHAR, count(*) FROM %s WHERE %s IS NOT NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d;", column, table, column, lowCardinalityLimit))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			value := &ValueCount{}
+			if err := rows.Scan(&value.Value, &value.Count); err != nil {
+				return nil, err
+			}
+			value.Value = truncateProfileValue(value.Value)
+			profile.TopValues = append(profile.TopValues, value)
+		}
+		return profile, rows.Err()
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT DISTINCT %s::VARCHAR FROM %s WHERE %s IS NOT NULL ORDER BY 1 LIMIT %d;", column, table, column, exampleValuesLimit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		profile.Examples = append(profile.Examples, truncateProfileValue(value))
+	}
+	return profile, rows.Err()
+}
+
+func truncateProfileValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= maxProfileValueLen {
+		return value
+	}
+	return string(runes[:maxProfileValueLen]) + "..."
+}
+
+// describe summarizes the profile for the SQL prompt, one line per column.
+func (p *DatasetProfile) describe() string {
+	if p == nil {
+		return ""
+	}
+
+	var result strings.Builder
+	for _, table := range p.Tables {
+		result.WriteString(fmt.Sprintf("Table %s (%d rows):\n", table.Name, table.Rows))
+		for _, column := range table.Columns {
+			details := []string{fmt.Sprintf("%d nulls", column.NullCount), fmt.Sprintf("%d distinct", column.DistinctCount)}
+			if column.Min != "" || column.Max != "" {
+				details = append(details, fmt.Sprintf("min %s, max %s", column.Min, column.Max))
+			}
+			if len(column.TopValues) > 0 {
+				values := make([]string, len(column.TopValues))
+				for i, value := range column.TopValues {
+					values[i] = fmt.Sprintf("%q (%d)", value.Value, value.Count)
+				}
+				details = append(details, "values: "+strings.Join(values, ", "))
+			} else if len(column.Examples) > 0 {
+				examples := make([]string, len(column.Examples))
+				for i, example := range column.Examples {
+					examples[i] = fmt.Sprintf("%q", example)
+				}
+				details = append(details, "e.g. "+strings.Join(examples, ", "))
+			}
+			result.WriteString(fmt.Sprintf("- %s: %s\n", column.Name, strings.Join(details, "; ")))
+		}
+	}
+	return result.String()
+}
+
+func (p *DatasetProfile) toProto() *proto.DatasetProfile {
+	if p == nil {
+		return nil
+	}
+
+	profile := &proto.DatasetProfile{}
+	for _, table := range p.Tables {
+		tableProfile := &proto.TableProfile{Name: table.Name, Rows: table.Rows}
+		for _, column := range table.Columns {
+			columnProfile := &proto.ColumnProfile{
+				Name:          column.Name,
+				Type:          column.Type,
+				NullCount:     column.NullCount,
+				DistinctCount: column.DistinctCount,
+				Min:           column.Min,
+				Max:           column.Max,
+				Examples:      column.Examples,
+			}
+			for _, value := range column.TopValues {
+				columnProfile.TopValues = append(columnProfile.TopValues, &proto.ValueCount{Value: value.Value, Count: value.Count})
+			}
+			tableProfile.Columns = append(tableProfile.Columns, columnProfile)
+		}
+		profile.Tables = append(profile.Tables, tableProfile)
+	}
+	return profile
+}
+
+// parseDatasetProfile reads the profile stored with a chat. Chats without a profile return nil.
+func parseDatasetProfile(stored string) *DatasetProfile {
+	if stored == "" {
+		return nil
+	}
+
+	var profile DatasetProfile
+	if err := json.Unmarshal([]byte(stored), &profile); err != nil {
+		log.Printf("Failed to parse dataset profile: %v", err)
+		return nil
+	}
+	return &profile
+}
+
+func (s Server) GetDatasetProfile(ctx context.Context, in *proto.GetDatasetProfile_Request) (*proto.GetDatasetProfile_Response, error) {
+	if in.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserID is required")
+	}
+
+	if in.ChatID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ChatID is required")
+	}
+
+	jChat, err := s.getOwnedChat(in.UserID, in.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := parseDatasetProfile(jChat.DatasetProfile)
+	if profile == nil {
+		// Small files are given to the model as they are and never loaded into DuckDB
+		return nil, status.Error(codes.NotFound, "No dataset profile for this chat")
+	}
+
+	return &proto.GetDatasetProfile_Response{Profile: profile.toProto()}, nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestProfileColumnListsEveryLowCardinalityValue(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+	if _, err := duckDB.Exec("CREATE TABLE json_data AS SELECT 'status_' || (range % 17) AS status FROM range(100);"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	profile, err := profileColumn(duckDB, "json_data", "status", "VARCHAR")
+	if err != nil {
+		t.Fatalf("Failed to profile column: %v", err)
+	}
+	if profile.DistinctCount != 17 || len(profile.TopValues) != 17 {
+		t.Errorf("Got %d distinct and %d values, want all 17 values listed", profile.DistinctCount, len(profile.TopValues))
+	}
+}
+
+func TestProfileDatasetSkipsColumnsThatFail(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+	if _, err := duckDB.Exec("CREATE TABLE json_data AS SELECT range AS id, 'a' AS name FROM range(5);"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	schema := newTableSchema(jsonTableName)
+	schema.Columns = []*tableColumn{{Name: "id"}, {Name: "meta.name_2"}, {Name: "name"}}
+
+	profile := profileDataset(duckDB, schema)
+	if len(profile.Tables) != 1 || profile.Tables[0].Rows != 5 {
+		t.Fatalf("Got %+v, want the 5 rows of json_data", profile.Tables)
+	}
+	columns := profile.Tables[0].Columns
+	if len(columns) != 2 || columns[0].Name != "id" || columns[0].Type != "BIGINT" || columns[1].Name != "name" {
+		t.Errorf("Got columns %+v, want id and name profiled with their types", columns)
+	}
+}
@@ -1,134 +1,377 @@
 package server
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"database/sql/driver"
 	"fmt"
 	"log"
-	"reflect"
 	"strings"
+
+	"github.com/marcboeker/go-duckdb"
 )
 
-func createTableFromJSON(db *sql.DB, jsonData interface{}) error {
-	switch data := jsonData.(type) {
-	case []interface{}:
-		if len(data) == 0 {
-			return fmt.Errorf("JSON array is empty")
+// LoadJSONIntoDuckDB creates the tables for the JSON and loads every record into them. recordPath is a JSON
+// Pointer to the records, when empty they are detected. The report tells which records could not be loaded.
+func LoadJSONIntoDuckDB(db *sql.DB, jsonData interface{}, ingestMode, recordPath string) (*IngestionReport, error) {
+	doc, err := extractRecords(jsonData, recordPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tableSchema, err := createTableFromJSON(db, doc, ingestMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table from JSON: %v", err)
+	}
+
+	report, err := insertDataIntoDuckDB(db, tableSchema, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert data into DuckDB: %v", err)
+	}
+	return report, nil
+}
+
+// createTableFromJSON creates the json_data table with a schema inferred from every record, so keys that only
+// appear in later records still get a column. In shred mode arrays of objects get their own child tables.
+func createTableFromJSON(db *sql.DB, doc *jsonDocument, ingestMode string) (*tableSchema, error) {
+	if len(doc.Records) == 0 {
+		return nil, fmt.Errorf("JSON array is empty")
+	}
+
+	records := make([]map[string]interface{}, 0, len(doc.Records))
+	for _, item := range doc.Records {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			records = append(records, itemMap)
 		}
-		// Assuming the first item in the slice gives us the structure of the JSON data
-		firstItem, ok := data[0].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("unexpected structure: expected map, got %T", data[0])
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("unexpected structure: expected objects, got %s", jsonKindName(doc.Records[0]))
+	}
+
+	schema := inferTableSchema(jsonTableName, records)
+	if ingestMode == ingestModeShred {
+		schema.shred()
+	}
+	if doc.Metadata != nil {
+		schema.Metadata = inferTableSchema(metadataTableName, []map[string]interface{}{doc.Metadata})
+	}
+
+	for _, table := range schema.tables() {
+		if err := createTable(db, table); err != nil {
+			return nil, err
 		}
-		return createTableForMap(db, firstItem)
-	case map[string]interface{}:
-		// If it's a single JSON object
-		return createTableForMap(db, data)
-	default:
-		return fmt.Errorf("unsupported JSON structure: %T", data)
 	}
+
+	if err := createMetadataTables(db, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
 }
 
-func createTableForMap(db *sql.DB, jsonMap map[string]interface{}) error {
-	createStmt := "CREATE TABLE IF NOT EXISTS json_data ("
-	for key, value := range jsonMap {
-		fieldType := determineFieldType(value)
-		createStmt += fmt.Sprintf("%s %s,", key, fieldType)
+func createTable(db *sql.DB, schema *tableSchema) error {
+	createStmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (", quoteIdentifier(schema.Name))
+	if schema.RowIDs {
+		createStmt += fmt.Sprintf("%s BIGINT,", quoteIdentifier(rowIDColumn))
+	}
+	if schema.Parent != nil {
+		createStmt += fmt.Sprintf("%s BIGINT,", quoteIdentifier(parentIDColumn))
+	}
+	for _, column := range schema.Columns {
+		createStmt += fmt.Sprintf("%s %s,", quoteIdentifier(column.Name), column.Type.SQL())
 	}
 
 	// Remove the last comma and close the statement
 	createStmt = strings.TrimRight(createStmt, ",") + ");"
 	_, err := db.Exec(createStmt)
 	if err != nil {
-		return fmt.Errorf("failed to create table: %v", err)
+		return fmt.Errorf("failed to create table %s: %v", schema.Name, err)
 	}
 	return nil
 }
 
-func determineFieldType(value interface{}) string {
-	switch reflect.TypeOf(value).Kind() {
-	case reflect.String:
-		return "TEXT"
-	case reflect.Float64:
-		return "DOUBLE"
-	case reflect.Int, reflect.Int64:
-		return "INTEGER"
-	case reflect.Bool:
-		return "BOOLEAN"
-	case reflect.Map, reflect.Slice:
-		// For nested objects or arrays, store them as serialized JSON strings
-		return "TEXT"
-	default:
-		return "TEXT" // Fallback to TEXT for unsupported types
+// createMetadataTables stores how the tables relate and which JSON key every column was created from, so queries
+// and answers can be related back to the user's own field names and the schema can be loaded from a prebuilt
+// database.
+func createMetadataTables(db *sql.DB, schema *tableSchema) error {
+	_, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (table_name VARCHAR, json_path VARCHAR, parent_table VARCHAR, parent_key VARCHAR);", tableMetadataTable))
+	if err != nil {
+		return fmt.Errorf("failed to create table metadata table: %v", err)
 	}
-}
 
-func insertMapIntoDuckDB(db *sql.DB, jsonMap map[string]interface{}) error {
-	columns := ""
-	values := ""
-	args := []interface{}{}
-	for key, value := range jsonMap {
-		columns += key + ","
-		values += "?,"
-
-		if value == nil {
-			// Handle nil values (e.g., store as NULL or empty string)
-			args = append(args, nil)
-			continue
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (table_name VARCHAR, column_name VARCHAR, json_key VARCHAR, detected_types VARCHAR);", columnMappingTable))
+	if err != nil {
+		return fmt.Errorf("failed to create column mapping table: %v", err)
+	}
+
+	for _, table := range schema.tables() {
+		parentTable := ""
+		if table.Parent != nil {
+			parentTable = table.Parent.Name
+		}
+		_, err := db.Exec(fmt.Sprintf("INSERT INTO %s VALUES (?, ?, ?, ?);", tableMetadataTable), table.Name, table.Path, parentTable, table.ParentKey)
+		if err != nil {
+			return fmt.Errorf("failed to insert table metadata: %v", err)
 		}
 
-		switch reflect.TypeOf(value).Kind() {
-		case reflect.Map, reflect.Slice:
-			// Serialize nested structures to JSON
-			serializedValue, err := json.Marshal(value)
+		for _, column := range table.Columns {
+			detectedTypes := strings.Join(column.describeDetectedTypes(), "\n")
+			_, err := db.Exec(fmt.Sprintf("INSERT INTO %s VALUES (?, ?, ?, ?);", columnMappingTable), table.Name, column.Name, column.Path, detectedTypes)
 			if err != nil {
-				return fmt.Errorf("failed to serialize nested structure for key %s: %v", key, err)
+				return fmt.Errorf("failed to insert column mapping: %v", err)
 			}
-			args = append(args, string(serializedValue)) // Store as string
-		default:
-			args = append(args, value)
 		}
 	}
+	return nil
+}
 
-	// Remove the last comma and build the final query
-	columns = strings.TrimRight(columns, ",")
-	values = strings.TrimRight(values, ",")
-	insertStmt := fmt.Sprintf("INSERT INTO json_data (%s) VALUES (%s);", columns, values)
+// loadTableSchema reads back the tables, column names and detected types stored by createMetadataTables. Column
+// types are not loaded, the schema text is read from DuckDB itself.
+func loadTableSchema(db *sql.DB) (*tableSchema, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT table_name, json_path, parent_table, parent_key FROM %s ORDER BY rowid;", tableMetadataTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table metadata: %v", err)
+	}
+	defer rows.Close()
 
-	_, err := db.Exec(insertStmt, args...)
+	var root *tableSchema
+	tables := make(map[string]*tableSchema)
+	for rows.Next() {
+		var name, path, parentTable, parentKey string
+		if err := rows.Scan(&name, &path, &parentTable, &parentKey); err != nil {
+			return nil, fmt.Errorf("failed to scan table metadata: %v", err)
+		}
+
+		table := newTableSchema(name)
+		table.Path = path
+		table.ParentKey = parentKey
+		if parent, ok := tables[parentTable]; ok {
+			table.Parent = parent
+			table.RowIDs = true
+			parent.RowIDs = true
+			parent.Children = append(parent.Children, table)
+		} else if root == nil {
+			root = table
+		} else {
+			root.Metadata = table
+		}
+		tables[name] = table
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no tables found in %s", tableMetadataTable)
+	}
+
+	columnRows, err := db.Query(fmt.Sprintf("SELECT table_name, column_name, json_key, detected_types FROM %s ORDER BY rowid;", columnMappingTable))
 	if err != nil {
-		return fmt.Errorf("failed to insert data: %v", err)
+		return nil, fmt.Errorf("failed to query column mapping: %v", err)
 	}
-	return nil
+	defer columnRows.Close()
+
+	for columnRows.Next() {
+		var tableName, detectedTypes string
+		column := &tableColumn{}
+		if err := columnRows.Scan(&tableName, &column.Name, &column.Path, &detectedTypes); err != nil {
+			return nil, fmt.Errorf("failed to scan column mapping: %v", err)
+		}
+		if detectedTypes != "" {
+			column.detectedTypes = strings.Split(detectedTypes, "\n")
+		}
+		if table, ok := tables[tableName]; ok {
+			table.Columns = append(table.Columns, column)
+		}
+	}
+	return root, columnRows.Err()
+}
+
+// tableRow is a row of one of the tables created for the JSON, in column order.
+type tableRow struct {
+	table  *tableSchema
+	values []driver.Value
 }
 
-func insertDataIntoDuckDB(db *sql.DB, jsonData interface{}) error {
-	switch data := jsonData.(type) {
-	case []interface{}:
-		for i, item := range data {
+// recordRows converts the record into its row and, in shred mode, the rows of its array elements in the child
+// tables. Row ids are assigned as the rows are built.
+func recordRows(schema *tableSchema, record map[string]interface{}, parentID int64, rows []tableRow) ([]tableRow, error) {
+	schema.nextRowID++
+	rowID := schema.nextRowID
+
+	values := make([]driver.Value, 0, len(schema.Columns)+2)
+	if schema.RowIDs {
+		values = append(values, rowID)
+	}
+	if schema.Parent != nil {
+		values = append(values, parentID)
+	}
+
+	for key := range record {
+		if _, ok := schema.index[key]; !ok && schema.child(key) == nil {
+			return nil, fmt.Errorf("no column for key %s", key)
+		}
+	}
+
+	for _, column := range schema.Columns {
+		value, err := appenderValue(column.Type, record[column.Key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert value for key %s: %v", column.Key, err)
+		}
+		values = append(values, value)
+	}
+	rows = append(rows, tableRow{table: schema, values: values})
+
+	for _, child := range schema.Children {
+		items, _ := record[child.ParentKey].([]interface{})
+		for _, item := range items {
 			itemMap, ok := item.(map[string]interface{})
 			if !ok {
-				// Log the error but continue with the next entry
-				log.Printf("Skipping entry %d: unexpected type in JSON array, expected map[string]interface{}, got %T", i, item)
 				continue
 			}
-			err := insertMapIntoDuckDB(db, itemMap)
+			var err error
+			rows, err = recordRows(child, itemMap, rowID, rows)
 			if err != nil {
-				// Log the error but continue with the next entry
-				log.Printf("Error inserting entry %d into DuckDB: %v", i, err)
-				continue
+				return nil, fmt.Errorf("failed to convert %s element: %v", child.Name, err)
 			}
 		}
-		return nil
-	case map[string]interface{}:
-		return insertMapIntoDuckDB(db, data)
-	default:
-		return fmt.Errorf("unsupported JSON structure for insertion: %T", jsonData)
 	}
+	return rows, nil
 }
 
-func queryDuckDB(db *sql.DB, query string) ([]map[string]interface{}, error) {
-	rows, err := db.Query(query)
+// insertDataIntoDuckDB loads the records through DuckDB's appender in a single transaction. Records that cannot be
+// converted to the table schema are skipped and listed in the report.
+func insertDataIntoDuckDB(db *sql.DB, schema *tableSchema, doc *jsonDocument) (*IngestionReport, error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DuckDB connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN TRANSACTION;"); err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	report := newIngestionReport(schema, len(doc.Records))
+	report.RecordPath = doc.Path
+	err = conn.Raw(func(driverConn any) error {
+		return appendRecords(driverConn.(driver.Conn), schema, doc, report)
+	})
+	if err != nil {
+		if _, rerr := conn.ExecContext(ctx, "ROLLBACK;"); rerr != nil {
+			log.Printf("Failed to roll back ingestion: %v", rerr)
+		}
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT;"); err != nil {
+		return nil, fmt.Errorf("failed to commit ingestion: %v", err)
+	}
+
+	if report.SkippedRecords > 0 {
+		log.Printf("Skipped %d of %d records while loading JSON into DuckDB", report.SkippedRecords, report.TotalRecords)
+	}
+	return report, nil
+}
+
+func appendRecords(driverConn driver.Conn, schema *tableSchema, doc *jsonDocument, report *IngestionReport) error {
+	appenders := make(map[*tableSchema]*duckdb.Appender)
+	closeAppenders := func() error {
+		var firstErr error
+		for table, appender := range appenders {
+			// Close flushes the rows that are still buffered
+			if err := appender.Close(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("failed to flush rows into %s: %v", table.Name, err)
+			}
+		}
+		appenders = nil
+		return firstErr
+	}
+	defer func() {
+		if appenders != nil {
+			_ = closeAppenders()
+		}
+	}()
+
+	for _, table := range schema.tables() {
+		appender, err := duckdb.NewAppenderFromConn(driverConn, "", table.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create appender for %s: %v", table.Name, err)
+		}
+		appenders[table] = appender
+	}
+
+	var rows []tableRow
+	for i, item := range doc.Records {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			// Record the error but continue with the next entry
+			report.skip(i, fmt.Sprintf("the record is %s, not a JSON object", jsonKindName(item)))
+			continue
+		}
+
+		var err error
+		rows, err = recordRows(schema, itemMap, 0, rows[:0])
+		if err != nil {
+			// Record the error but continue with the next entry
+			report.skip(i, err.Error())
+			continue
+		}
+
+		for _, row := range rows {
+			if err := appenders[row.table].AppendRow(row.values...); err != nil {
+				return fmt.Errorf("failed to append entry %d into %s: %v", i, row.table.Name, err)
+			}
+			report.addRow(row.table.Name)
+		}
+		report.LoadedRecords++
+	}
+
+	if schema.Metadata != nil {
+		rows, err := recordRows(schema.Metadata, doc.Metadata, 0, nil)
+		if err != nil {
+			return fmt.Errorf("failed to convert JSON metadata: %v", err)
+		}
+		if err := appenders[schema.Metadata].AppendRow(rows[0].values...); err != nil {
+			return fmt.Errorf("failed to append JSON metadata: %v", err)
+		}
+		report.addRow(schema.Metadata.Name)
+	}
+
+	return closeAppenders()
+}
+
+// describeTables returns the schema text of every table created for the JSON, including how child tables
+// relate to their parents.
+func describeTables(db *sql.DB, schema *tableSchema) (string, error) {
+	var result strings.Builder
+	for _, table := range schema.tables() {
+		tableSchemaText, err := getTableSchema(db, table.Name)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(fmt.Sprintf("Table %s: %s\n", table.Name, tableSchemaText))
+	}
+
+	if relationships := schema.describeRelationships(); relationships != "" {
+		result.WriteString("\nRelationships:\n" + relationships)
+	}
+
+	if detectedTypes := schema.describeDetectedTypes(); detectedTypes != "" {
+		result.WriteString("\nTypes detected from the format of the JSON values:\n" + detectedTypes)
+	}
+
+	if mapping := schema.describeColumnMapping(); mapping != "" {
+		result.WriteString("\nColumn names were normalized from the JSON keys:\n" + mapping)
+	}
+	return result.String(), nil
+}
+
+// queryDuckDB runs the query and collects rows until the limits are reached. DuckDB computes the whole result before
+// the first row is read, so the query is limited to omittedRowsProbe rows beyond MaxRows and only those are counted
+// as omitted. Cancelling ctx interrupts the running query.
+func queryDuckDB(ctx context.Context, db *sql.DB, query string, limits queryLimits) (*queryResult, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM (\n%s\n) LIMIT %d;", query, limits.MaxRows+omittedRowsProbe))
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +382,14 @@ func queryDuckDB(db *sql.DB, query string) ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	results := []map[string]interface{}{}
+	result := &queryResult{Columns: columns}
+	size := len(result.markdown())
 	for rows.Next() {
+		if len(result.Rows) >= limits.MaxRows {
+			result.OmittedRows++
+			break
+		}
+
 		// Create a slice of interfaces to hold the row data
 		row := make([]interface{}, len(columns))
 		rowPointers := make([]interface{}, len(columns))
@@ -152,15 +401,33 @@ func queryDuckDB(db *sql.DB, query string) ([]map[string]interface{}, error) {
 			return nil, err
 		}
 
-		// Convert row into a map
-		rowMap := make(map[string]interface{})
-		for i, colName := range columns {
-			rowMap[colName] = row[i]
+		for i := range row {
+			row[i] = resultValue(row[i])
 		}
-		results = append(results, rowMap)
+
+		rowSize := len(markdownRow(formatResultRow(row)))
+		if size+rowSize > limits.MaxBytes {
+			if len(result.Rows) > 0 {
+				result.OmittedRows++
+				break
+			}
+			// The first row is always given to the model, shorten its longest values so it fits
+			result.TruncatedCells = truncateResultRow(row, (limits.MaxBytes-size)/len(columns))
+			rowSize = len(markdownRow(formatResultRow(row)))
+		}
+		size += rowSize
+		result.Rows = append(result.Rows, row)
 	}
 
-	return results, nil
+	// Count the rest of the probe for the omitted rows marker
+	for result.OmittedRows > 0 && rows.Next() {
+		result.OmittedRows++
+	}
+	if result.OmittedRows > 0 && int64(len(result.Rows))+result.OmittedRows >= int64(limits.MaxRows+omittedRowsProbe) {
+		result.OmittedRowsCapped = true
+	}
+
+	return result, rows.Err()
 }
 
 func getTableSchema(db *sql.DB, tableName string) (string, error) {
@@ -196,7 +463,7 @@ func getTableSchema(db *sql.DB, tableName string) (string, error) {
 
 		rowMap := make(map[string]interface{})
 		for i, colName := range columns {
-			rowMap[colName] = row[i]
+			rowMap[colName] = resultValue(row[i])
 		}
 		schema = append(schema, rowMap)
 
@@ -206,112 +473,3 @@ func getTableSchema(db *sql.DB, tableName string) (string, error) {
 	schemaText = strings.TrimRight(schemaText, ", ")
 	return schemaText, nil
 }
-
-func flattenJSONFields(jsonData map[string]interface{}, prefix string, column string, uniqueFields map[string]map[string]struct{}) {
-	for key, value := range jsonData {
-		newKey := key
-		if prefix != "" {
-			newKey = prefix + "." + key
-		}
-
-		switch v := value.(type) {
-		case map[string]interface{}:
-			// Recursively flatten nested maps
-			flattenJSONFields(v, newKey, column, uniqueFields)
-		default:
-			// Add the flattened key to the unique fields map, grouped by column
-			if _, ok := uniqueFields[column]; !ok {
-				uniqueFields[column] = make(map[string]struct{})
-			}
-			uniqueFields[column][newKey] = struct{}{} // Ensure the key is unique
-		}
-	}
-}
-
-// Function to check if a string contains valid JSON
-func isValidJSON(str string) bool {
-	var js map[string]interface{}
-	err := json.Unmarshal([]byte(str), &js)
-	return err == nil
-}
-
-// Function to dynamically detect JSON columns and extract unique nested fields
-func extractUniqueFieldsFromJSONColumns(db *sql.DB, tableName string) (string, error) {
-	uniqueFields := make(map[string]map[string]struct{})
-	query := fmt.Sprintf("PRAGMA table_info(%s);", tableName)
-
-	// Query the table schema to identify columns
-	rows, err := db.Query(query)
-	if err != nil {
-		return "", fmt.Errorf("failed to query table schema: %v", err)
-	}
-	defer rows.Close()
-
-	columns := make([]string, 0)
-	for rows.Next() {
-		var cid int
-		var name, colType, notnull, pk string
-		var dfltValue sql.NullString // Handling potential NULL for dflt_value
-
-		// Adjust the scan to use sql.NullString for nullable fields
-		err := rows.Scan(&cid, &name, &colType, &notnull, &dfltValue, &pk)
-		if err != nil {
-			log.Printf("Failed to scan table schema: %v", err)
-			continue
-		}
-
-		// Add VARCHAR or TEXT columns that could contain JSON to the list
-		if colType == "VARCHAR" || colType == "TEXT" {
-			columns = append(columns, name)
-		}
-	}
-
-	// Iterate over JSON-like columns to extract and flatten fields
-	for _, col := range columns {
-		query := fmt.Sprintf("SELECT %s FROM %s;", col, tableName)
-		rows, err := db.Query(query)
-		if err != nil {
-			log.Printf("Failed to query column %s: %v", col, err)
-			continue
-		}
-		defer rows.Close()
-
-		// Process each row in the column
-		for rows.Next() {
-			var jsonStr string
-			err := rows.Scan(&jsonStr)
-			if err != nil {
-				log.Printf("Failed to scan row for column %s: %v", col, err)
-				continue
-			}
-
-			// Check if the string is valid JSON
-			if !isValidJSON(jsonStr) {
-				continue
-			}
-
-			// Attempt to parse the JSON string
-			var jsonData map[string]interface{}
-			err = json.Unmarshal([]byte(jsonStr), &jsonData)
-			if err != nil {
-				// If it's not valid JSON, skip this row
-				log.Printf("Invalid JSON in column %s: %v", col, err)
-				continue
-			}
-
-			// Flatten the JSON and track unique fields
-			flattenJSONFields(jsonData, "", col, uniqueFields)
-		}
-	}
-
-	// Build a structured string of unique fields by column
-	var result strings.Builder
-	for col, fields := range uniqueFields {
-		result.WriteString(fmt.Sprintf("Column: %s\n", col))
-		for field := range fields {
-			result.WriteString(fmt.Sprintf("  - %s\n", field))
-		}
-	}
-
-	return result.String(), nil
-}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func openTestDuckDB(t *testing.T) *sql.DB {
+	t.Helper()
+	duckDB, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("Failed to open DuckDB: %v", err)
+	}
+	t.Cleanup(func() { duckDB.Close() })
+	return duckDB
+}
+
+func TestQueryDuckDBStopsAtRowLimit(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+
+	// 10^12 rows, computing the whole result would never finish
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	result, err := queryDuckDB(ctx, duckDB, "SELECT a.range AS a, b.range AS b FROM range(1000000) a, range(1000000) b;", queryLimits{MaxRows: 5, MaxBytes: 8192})
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Query took %s", elapsed)
+	}
+
+	if len(result.Rows) != 5 {
+		t.Errorf("Got %d rows, want 5", len(result.Rows))
+	}
+	if result.OmittedRows != omittedRowsProbe || !result.OmittedRowsCapped {
+		t.Errorf("Got %d omitted rows (capped %v), want %d capped", result.OmittedRows, result.OmittedRowsCapped, omittedRowsProbe)
+	}
+}
+
+func TestQueryDuckDBCountsOmittedRows(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+
+	result, err := queryDuckDB(context.Background(), duckDB, "SELECT range AS n FROM range(30) ORDER BY n DESC", queryLimits{MaxRows: 10, MaxBytes: 8192})
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+
+	if len(result.Rows) != 10 || result.OmittedRows != 20 || result.OmittedRowsCapped {
+		t.Errorf("Got %d rows and %d omitted rows (capped %v), want 10 and 20", len(result.Rows), result.OmittedRows, result.OmittedRowsCapped)
+	}
+	if result.Rows[0][0] != int64(29) {
+		t.Errorf("Got first row %v, want the order of the query", result.Rows[0])
+	}
+}
+
+func TestQueryDuckDBTruncatesOversizedFirstRow(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+
+	limits := queryLimits{MaxRows: 10, MaxBytes: 200}
+	result, err := queryDuckDB(context.Background(), duckDB, "SELECT 1 AS id, repeat('é', 5000) AS body, 'short' AS name FROM range(3)", limits)
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+
+	if len(result.Rows) != 1 || result.OmittedRows != 2 {
+		t.Fatalf("Got %d rows and %d omitted rows, want 1 and 2", len(result.Rows), result.OmittedRows)
+	}
+	if !result.TruncatedCells {
+		t.Errorf("Result is not marked as truncated")
+	}
+	body, ok := result.Rows[0][1].(string)
+	if !ok || !strings.HasSuffix(body, truncationMarker) || !utf8.ValidString(body) {
+		t.Errorf("Got body %q, want valid UTF-8 ending in %q", body, truncationMarker)
+	}
+	if result.Rows[0][2] != "short" {
+		t.Errorf("Got name %v, want the short value kept", result.Rows[0][2])
+	}
+
+	markdown := result.markdown()
+	if !strings.Contains(markdown, "were truncated") {
+		t.Errorf("Markdown does not mention the truncation: %q", markdown)
+	}
+	if size := len(markdownRow(formatResultRow(result.Rows[0]))); size > limits.MaxBytes {
+		t.Errorf("Truncated row is %d bytes, want at most %d", size, limits.MaxBytes)
+	}
+}
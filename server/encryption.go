@@ -0,0 +1,259 @@
+package server
+
+import (
+	"JsonAI/db"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const (
+	encryptionMetadataKey = "jai-encryption"
+	ownerMetadataKey      = "jai-owner"
+	plainSizeMetadataKey  = "jai-plaintext-size"
+	encryptionAlgorithm   = "AES256-GCM-AAD" // The owner and object key are authenticated with the content
+	legacyAlgorithm       = "AES256-GCM"     // Objects encrypted without additional data
+	dataKeySize           = 32
+)
+
+// Keyring holds the master keys used to wrap per-user data keys. Objects are encrypted with the user's data key,
+// so rotating the master key only re-wraps the data keys stored on each user.
+type Keyring struct {
+	current  *masterKey
+	previous []*masterKey
+}
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// newKeyring parses the base64 encoded 256-bit master keys. An empty current key disables encryption.
+func newKeyring(currentKey string, previousKeys string) (*Keyring, error) {
+	keyring := &Keyring{}
+	if currentKey == "" {
+		return keyring, nil
+	}
+
+	current, err := parseMasterKey(currentKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %v", err)
+	}
+	keyring.current = current
+
+	for _, encoded := range strings.Split(previousKeys, ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		previous, err := parseMasterKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous master key: %v", err)
+		}
+		keyring.previous = append(keyring.previous, previous)
+	}
+
+	return keyring, nil
+}
+
+func parseMasterKey(encoded string) (*masterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", dataKeySize, len(key))
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := sha256.Sum256(key)
+	return &masterKey{id: hex.EncodeToString(fingerprint[:4]), aead: aead}, nil
+}
+
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.current != nil
+}
+
+func (k *Keyring) wrap(dataKey []byte) (string, string, error) {
+	wrapped, err := seal(k.current.aead, dataKey, nil)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(wrapped), k.current.id, nil
+}
+
+func (k *Keyring) unwrap(wrapped, masterKeyID string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped data key: %v", err)
+	}
+
+	for _, key := range append([]*masterKey{k.current}, k.previous...) {
+		if key.id == masterKeyID {
+			return open(key.aead, ciphertext, nil)
+		}
+	}
+	return nil, fmt.Errorf("master key %s is not configured", masterKeyID)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and prefixes the result with the random nonce. additionalData is authenticated but not
+// encrypted, open fails unless it is given the same.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
+
+// objectAdditionalData binds an object's ciphertext to its owner and S3 key, so it cannot be decrypted as another
+// user's object or under another key.
+func objectAdditionalData(userID, key string) []byte {
+	return []byte(userID + "\x00" + key)
+}
+
+// encryptObject encrypts the content of the user's object stored under key with the user's data key.
+func encryptObject(dataKey []byte, userID, key string, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, plaintext, objectAdditionalData(userID, key))
+}
+
+// decryptObject decrypts an object stored under key, reading its owner and algorithm from the object metadata.
+func decryptObject(dataKey []byte, metadata map[string]string, key string, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch metadata[encryptionMetadataKey] {
+	case encryptionAlgorithm:
+		return open(aead, ciphertext, objectAdditionalData(metadata[ownerMetadataKey], key))
+	case legacyAlgorithm:
+		return open(aead, ciphertext, nil)
+	default:
+		return nil, fmt.Errorf("unknown encryption algorithm %q", metadata[encryptionMetadataKey])
+	}
+}
+
+// userDataKey returns the user's unwrapped data key, generating and storing one on first use.
+func (s Server) userDataKey(userID string) ([]byte, error) {
+	user, err := db.GetUserByID(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DataKey == "" {
+		dataKey := make([]byte, dataKeySize)
+		if _, err := rand.Read(dataKey); err != nil {
+			return nil, fmt.Errorf("failed to generate data key: %v", err)
+		}
+		wrapped, masterKeyID, err := s.Keys.wrap(dataKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap data key: %v", err)
+		}
+
+		created, err := db.SetUserDataKeyIfEmpty(s.DB, userID, wrapped, masterKeyID)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return dataKey, nil
+		}
+
+		// Another request stored a key first, use that one
+		user, err = db.GetUserByID(s.DB, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Keys.unwrap(user.DataKey, user.DataKeyMasterID)
+}
+
+func (s Server) encryptForUser(userID, key string, plaintext []byte) ([]byte, error) {
+	dataKey, err := s.userDataKey(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key: %v", err)
+	}
+	return encryptObject(dataKey, userID, key, plaintext)
+}
+
+// decryptForUser decrypts an object stored under key with the data key of the owner in its metadata.
+func (s Server) decryptForUser(metadata map[string]string, key string, ciphertext []byte) ([]byte, error) {
+	if !s.Keys.Enabled() {
+		return nil, errors.New("object is encrypted but no master key is configured")
+	}
+	dataKey, err := s.userDataKey(metadata[ownerMetadataKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key: %v", err)
+	}
+	return decryptObject(dataKey, metadata, key, ciphertext)
+}
+
+// RotateDataKeys re-wraps every user's data key with the current master key. Stored objects keep their
+// ciphertext since the data keys themselves do not change. The old master key must still be listed in
+// JAI_PREVIOUS_MASTER_KEYS while this runs.
+func (s Server) RotateDataKeys() error {
+	if !s.Keys.Enabled() {
+		return errors.New("JAI_MASTER_KEY is not configured")
+	}
+
+	users, err := db.GetUsersWithDataKeys(s.DB)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %v", err)
+	}
+
+	rotated := 0
+	for _, user := range users {
+		if user.DataKeyMasterID == s.Keys.current.id {
+			continue
+		}
+
+		dataKey, err := s.Keys.unwrap(user.DataKey, user.DataKeyMasterID)
+		if err != nil {
+			return fmt.Errorf("failed to unwrap data key for user %s: %v", user.UUID.ID, err)
+		}
+		wrapped, masterKeyID, err := s.Keys.wrap(dataKey)
+		if err != nil {
+			return fmt.Errorf("failed to wrap data key for user %s: %v", user.UUID.ID, err)
+		}
+		err = db.UpdateUserDataKey(s.DB, user.UUID.ID, wrapped, masterKeyID)
+		if err != nil {
+			return fmt.Errorf("failed to store data key for user %s: %v", user.UUID.ID, err)
+		}
+		rotated++
+	}
+
+	log.Printf("Re-wrapped %d of %d data keys with master key %s", rotated, len(users), s.Keys.current.id)
+	return nil
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+)
+
+func newTestMasterKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestKeyringWrapsAndUnwrapsDataKeys(t *testing.T) {
+	keyring, err := newKeyring(newTestMasterKey(t), "")
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	dataKey := bytes.Repeat([]byte{7}, dataKeySize)
+
+	wrapped, masterKeyID, err := keyring.wrap(dataKey)
+	if err != nil {
+		t.Fatalf("Failed to wrap data key: %v", err)
+	}
+	unwrapped, err := keyring.unwrap(wrapped, masterKeyID)
+	if err != nil || !bytes.Equal(unwrapped, dataKey) {
+		t.Fatalf("Got data key %x and error %v, want %x", unwrapped, err, dataKey)
+	}
+
+	if _, err := keyring.unwrap(wrapped, "unknown"); err == nil {
+		t.Errorf("A data key wrapped by an unknown master key was unwrapped")
+	}
+	other, err := newKeyring(newTestMasterKey(t), "")
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	if _, err := other.unwrap(wrapped, masterKeyID); err == nil {
+		t.Errorf("A data key was unwrapped by a keyring without its master key")
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey, newKey := newTestMasterKey(t), newTestMasterKey(t)
+	oldKeyring, err := newKeyring(oldKey, "")
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	dataKey := bytes.Repeat([]byte{3}, dataKeySize)
+	wrapped, oldID, err := oldKeyring.wrap(dataKey)
+	if err != nil {
+		t.Fatalf("Failed to wrap data key: %v", err)
+	}
+
+	// After rotation the old master key is only listed as a previous key
+	rotated, err := newKeyring(newKey, " ,"+oldKey)
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	unwrapped, err := rotated.unwrap(wrapped, oldID)
+	if err != nil || !bytes.Equal(unwrapped, dataKey) {
+		t.Fatalf("Got data key %x and error %v with the previous master key, want %x", unwrapped, err, dataKey)
+	}
+
+	rewrapped, newID, err := rotated.wrap(unwrapped)
+	if err != nil {
+		t.Fatalf("Failed to re-wrap data key: %v", err)
+	}
+	if newID == oldID {
+		t.Errorf("Re-wrapped data key uses master key %s, want the current one", newID)
+	}
+	withoutOld, err := newKeyring(newKey, "")
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	if unwrapped, err := withoutOld.unwrap(rewrapped, newID); err != nil || !bytes.Equal(unwrapped, dataKey) {
+		t.Errorf("Got data key %x and error %v without the previous master key, want %x", unwrapped, err, dataKey)
+	}
+}
+
+func TestEncryptObjectRoundTripAndTampering(t *testing.T) {
+	dataKey := bytes.Repeat([]byte{9}, dataKeySize)
+	plaintext := []byte(`[{"name":"Widget"}]`)
+	metadata := map[string]string{encryptionMetadataKey: encryptionAlgorithm, ownerMetadataKey: "user-1"}
+
+	ciphertext, err := encryptObject(dataKey, "user-1", "user-1/products.json", plaintext)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	decrypted, err := decryptObject(dataKey, metadata, "user-1/products.json", ciphertext)
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Got %q and error %v, want %q", decrypted, err, plaintext)
+	}
+
+	flipped := bytes.Clone(ciphertext)
+	flipped[len(flipped)-1] ^= 1
+	otherOwner := map[string]string{encryptionMetadataKey: encryptionAlgorithm, ownerMetadataKey: "user-2"}
+	legacy := map[string]string{encryptionMetadataKey: legacyAlgorithm, ownerMetadataKey: "user-1"}
+	tests := []struct {
+		name       string
+		dataKey    []byte
+		metadata   map[string]string
+		key        string
+		ciphertext []byte
+	}{
+		{"flipped byte", dataKey, metadata, "user-1/products.json", flipped},
+		{"truncated", dataKey, metadata, "user-1/products.json", ciphertext[:10]},
+		{"other owner", dataKey, otherOwner, "user-1/products.json", ciphertext},
+		{"other object key", dataKey, metadata, "user-1/other.json", ciphertext},
+		{"other data key", bytes.Repeat([]byte{8}, dataKeySize), metadata, "user-1/products.json", ciphertext},
+		{"without additional data", dataKey, legacy, "user-1/products.json", ciphertext},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := decryptObject(test.dataKey, test.metadata, test.key, test.ciphertext); err == nil {
+				t.Errorf("Tampered object was decrypted")
+			}
+		})
+	}
+}
+
+func TestDecryptObjectWithoutAdditionalData(t *testing.T) {
+	dataKey := bytes.Repeat([]byte{5}, dataKeySize)
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	ciphertext, err := seal(aead, []byte("{}"), nil)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+
+	metadata := map[string]string{encryptionMetadataKey: legacyAlgorithm, ownerMetadataKey: "user-1"}
+	if plaintext, err := decryptObject(dataKey, metadata, "user-1/old.json", ciphertext); err != nil || string(plaintext) != "{}" {
+		t.Errorf("Got %q and error %v for an object encrypted without additional data", plaintext, err)
+	}
+}
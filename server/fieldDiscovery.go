@@ -0,0 +1,204 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	// Rows sampled from every column that may hold JSON text
+	fieldDiscoverySampleRows = 1000
+
+	// Elements of each array that are walked, the shape of an array rarely changes after the first elements
+	fieldDiscoveryArrayElements = 100
+
+	// Fields listed in the schema prompt across all columns
+	maxDiscoveredFields = 200
+)
+
+// discoveredField is a path inside the JSON held by a text column, e.g. items[].sku.
+type discoveredField struct {
+	Path  string
+	Types map[string]bool // JSON kinds seen at the path
+	Rows  int             // Sampled rows the path appears in
+}
+
+// extractUniqueFieldsFromJSONColumns samples the VARCHAR and JSON columns of every table for JSON text and lists
+// the nested paths found, walking into arrays, with the types seen at each path and how often it occurs.
+func extractUniqueFieldsFromJSONColumns(db *sql.DB, schema *tableSchema) (string, error) {
+	var result strings.Builder
+	listed := 0
+	omitted := 0
+	for _, table := range schema.tables() {
+		columns, err := textColumns(db, table.Name)
+		if err != nil {
+			return "", err
+		}
+
+		for _, column := range columns {
+			fields, sampled, err := discoverJSONFields(db, table.Name, column)
+			if err != nil {
+				log.Printf("Failed to discover fields of %s.%s: %v", table.Name, column, err)
+				continue
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			if listed >= maxDiscoveredFields {
+				omitted += len(fields)
+				continue
+			}
+			result.WriteString(fmt.Sprintf("Column: %s.%s (%d sampled values)\n", table.Name, column, sampled))
+			for _, field := range fields {
+				if listed >= maxDiscoveredFields {
+					omitted++
+					continue
+				}
+				types := make([]string, 0, len(field.Types))
+				for kind := range field.Types {
+					types = append(types, kind)
+				}
+				sort.Strings(types)
+				result.WriteString(fmt.Sprintf("  - %s: %s (in %d%% of values)\n", field.Path, strings.Join(types, " | "), field.Rows*100/sampled))
+				listed++
+			}
+		}
+	}
+
+	if omitted > 0 {
+		result.WriteString(fmt.Sprintf("(%d more fields omitted)\n", omitted))
+	}
+	return result.String(), nil
+}
+
+// textColumns returns the columns of the table that could hold JSON text.
+func textColumns(db *sql.DB, tableName string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info('%s');", strings.ReplaceAll(tableName, "'", "''")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table schema: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notnull, pk bool
+		var dfltValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notnull, &dfltValue, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan table schema: %v", err)
+		}
+
+		if colType == "VARCHAR" || colType == "TEXT" || colType == "JSON" {
+			columns = append(columns, name)
+		}
+	}
+	return columns, rows.Err()
+}
+
+// discoverJSONFields samples values of the column that look like JSON objects or arrays and returns the paths
+// found in them, most frequent first, with the number of values that parsed.
+func discoverJSONFields(db *sql.DB, tableName, column string) ([]*discoveredField, int, error) {
+	query := fmt.Sprintf(`SELECT value FROM (
+	SELECT %s::VARCHAR AS value FROM %s WHERE ltrim(%s::VARCHAR)[1] IN ('{', '[')
+) USING SAMPLE reservoir(%d ROWS) REPEATABLE (42);`, quoteIdentifier(column), quoteIdentifier(tableName), quoteIdentifier(column), fieldDiscoverySampleRows)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	fields := make(map[string]*discoveredField)
+	sampled := 0
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, 0, err
+		}
+
+		var jsonData interface{}
+		if err := json.Unmarshal([]byte(value), &jsonData); err != nil {
+			continue
+		}
+		sampled++
+
+		seen := make(map[string]bool)
+		walkJSONFields(jsonData, "", fields, seen)
+		for path := range seen {
+			fields[path].Rows++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	sorted := make([]*discoveredField, 0, len(fields))
+	for _, field := range fields {
+		sorted = append(sorted, field)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Rows != sorted[j].Rows {
+			return sorted[i].Rows > sorted[j].Rows
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted, sampled, nil
+}
+
+// walkJSONFields records the kind of every value below path. Objects and arrays are walked into and only
+// recorded themselves when they are empty.
+func walkJSONFields(value interface{}, path string, fields map[string]*discoveredField, seen map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			for key, item := range v {
+				childPath := key
+				if path != "" {
+					childPath = path + "." + key
+				}
+				walkJSONFields(item, childPath, fields, seen)
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for _, item := range v[:min(len(v), fieldDiscoveryArrayElements)] {
+				walkJSONFields(item, path+"[]", fields, seen)
+			}
+			return
+		}
+	}
+
+	if path == "" {
+		return
+	}
+	field, ok := fields[path]
+	if !ok {
+		field = &discoveredField{Path: path, Types: make(map[string]bool)}
+		fields[path] = field
+	}
+	field.Types[jsonTypeName(value)] = true
+	seen[path] = true
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"context"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"strings"
+)
+
+const maxSampleIndexes = 5
+
+// IngestionReport records how much of the JSON was loaded into DuckDB, so users and the model know when the
+// data they query is incomplete.
+type IngestionReport struct {
+	RecordPath     string           `json:"recordPath"` // JSON Pointer of the loaded records, empty for the whole document
+	TotalRecords   int              `json:"totalRecords"`
+	LoadedRecords  int              `json:"loadedRecords"`
+	SkippedRecords int              `json:"skippedRecords"`
+	Skipped        []*SkipReason    `json:"skipped,omitempty"`
+	Tables         []*IngestedTable `json:"tables"`
+}
+
+type SkipReason struct {
+	Reason        string `json:"reason"`
+	Count         int    `json:"count"`
+	SampleIndexes []int  `json:"sampleIndexes"`
+}
+
+type IngestedTable struct {
+	Name    string            `json:"name"`
+	Rows    int64             `json:"rows"`
+	Columns []*IngestedColumn `json:"columns"`
+
+	// Nested fields renamed because their keys only differ in case, named by their path like meta.name_2. They
+	// are not columns of the table.
+	RenamedFields []*IngestedColumn `json:"renamedFields,omitempty"`
+}
+
+type IngestedColumn struct {
+	Name    string `json:"name"`
+	JSONKey string `json:"jsonKey"`
+	Type    string `json:"type"`
+}
+
+func newIngestionReport(schema *tableSchema, totalRecords int) *IngestionReport {
+	report := &IngestionReport{TotalRecords: totalRecords}
+	for _, table := range schema.tables() {
+		ingestedTable := &IngestedTable{Name: table.Name}
+		for _, column := range table.Columns {
+			ingestedTable.Columns = append(ingestedTable.Columns, &IngestedColumn{
+				Name:    column.Name,
+				JSONKey: column.Path,
+				Type:    column.Type.SQL(),
+			})
+			for _, field := range column.renamedFields() {
+				ingestedTable.RenamedFields = append(ingestedTable.RenamedFields, &IngestedColumn{
+					Name:    field.Name,
+					JSONKey: field.Key,
+					Type:    field.Type.SQL(),
+				})
+			}
+		}
+		report.Tables = append(report.Tables, ingestedTable)
+	}
+	return report
+}
+
+// skip records that the record at index was not loaded. Records are grouped by reason and only the first few
+// indexes of each reason are kept.
+func (r *IngestionReport) skip(index int, reason string) {
+	r.SkippedRecords++
+	for _, skipped := range r.Skipped {
+		if skipped.Reason == reason {
+			skipped.Count++
+			if len(skipped.SampleIndexes) < maxSampleIndexes {
+				skipped.SampleIndexes = append(skipped.SampleIndexes, index)
+			}
+			return
+		}
+	}
+	r.Skipped = append(r.Skipped, &SkipReason{Reason: reason, Count: 1, SampleIndexes: []int{index}})
+}
+
+func (r *IngestionReport) addRow(tableName string) {
+	for _, table := range r.Tables {
+		if table.Name == tableName {
+			table.Rows++
+			return
+		}
+	}
+}
+
+// summary describes missing data for the prompts. It is empty when every record was loaded.
+func (r *IngestionReport) summary() string {
+	if r == nil || r.SkippedRecords == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Only %d of the %d records in the JSON were loaded, %d records were skipped:\n", r.LoadedRecords, r.TotalRecords, r.SkippedRecords))
+	for _, skipped := range r.Skipped {
+		indexes := make([]string, len(skipped.SampleIndexes))
+		for i, index := range skipped.SampleIndexes {
+			indexes[i] = fmt.Sprint(index)
+		}
+		result.WriteString(fmt.Sprintf("- %d records: %s (e.g. records %s)\n", skipped.Count, skipped.Reason, strings.Join(indexes, ", ")))
+	}
+	return result.String()
+}
+
+func (r *IngestionReport) toProto() *proto.IngestionReport {
+	if r == nil {
+		return nil
+	}
+
+	report := &proto.IngestionReport{
+		TotalRecords:   int32(r.TotalRecords),
+		LoadedRecords:  int32(r.LoadedRecords),
+		SkippedRecords: int32(r.SkippedRecords),
+		RecordPath:     r.RecordPath,
+	}
+	for _, skipped := range r.Skipped {
+		indexes := make([]int32, len(skipped.SampleIndexes))
+		for i, index := range skipped.SampleIndexes {
+			indexes[i] = int32(index)
+		}
+		report.Skipped = append(report.Skipped, &proto.SkippedRecords{
+			Reason:        skipped.Reason,
+			Count:         int32(skipped.Count),
+			SampleIndexes: indexes,
+		})
+	}
+	for _, table := range r.Tables {
+		ingestedTable := &proto.IngestedTable{Name: table.Name, Rows: table.Rows}
+		for _, column := range table.Columns {
+			ingestedTable.Columns = append(ingestedTable.Columns, &proto.IngestedColumn{
+				Name:    column.Name,
+				JsonKey: column.JSONKey,
+				Type:    column.Type,
+			})
+		}
+		for _, field := range table.RenamedFields {
+			ingestedTable.RenamedFields = append(ingestedTable.RenamedFields, &proto.IngestedColumn{
+				Name:    field.Name,
+				JsonKey: field.JSONKey,
+				Type:    field.Type,
+			})
+		}
+		report.Tables = append(report.Tables, ingestedTable)
+	}
+	return report
+}
+
+// parseIngestionReport reads the report stored with a chat. Chats without a report return nil.
+func parseIngestionReport(stored string) *IngestionReport {
+	if stored == "" {
+		return nil
+	}
+
+	var report IngestionReport
+	if err := json.Unmarshal([]byte(stored), &report); err != nil {
+		log.Printf("Failed to parse ingestion report: %v", err)
+		return nil
+	}
+	return &report
+}
+
+func (s Server) GetIngestionReport(ctx context.Context, in *proto.GetIngestionReport_Request) (*proto.GetIngestionReport_Response, error) {
+	if in.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserID is required")
+	}
+
+	if in.ChatID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ChatID is required")
+	}
+
+	jChat, err := s.getOwnedChat(in.UserID, in.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := parseIngestionReport(jChat.IngestionReport)
+	if report == nil {
+		// Small files are given to the model as they are and never loaded into DuckDB
+		return nil, status.Error(codes.NotFound, "No ingestion report for this chat")
+	}
+
+	return &proto.GetIngestionReport_Response{Report: report.toProto()}, nil
+}
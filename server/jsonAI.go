@@ -1,14 +1,20 @@
 package server
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/sashabaranov/go-openai"
 	"log"
 	"strings"
+	"time"
 )
 
-func (s Server) ConvertUserQuestionToSQLAndRetrieveQueryResults(duckDB *sql.DB, userQuestion, tableName, schema, jsonPreview string) (string, string, error) {
+// sqlQueryGuidance tells the model how the JSON was loaded into DuckDB and which queries are allowed. It is part
+// of every SQL generation prompt.
+const sqlQueryGuidance = `Nested JSON objects are stored as STRUCT columns and arrays as LIST columns, the schema shows their full nested type. Access STRUCT fields with dot notation (e.g. address.city, quote names with special characters like address."zip code") and expand LIST columns with unnest(). Columns with the JSON type hold values whose shape differs between records, read them with json_extract_string. When the schema lists more than one table, arrays of objects were split into child tables; join a child table to its parent as described under Relationships. Column names may have been normalized from the JSON keys, always use the column names from the schema in the query. DATE, TIMESTAMP and DECIMAL columns were parsed from JSON strings or epoch milliseconds as listed in the schema, compare and aggregate them directly (e.g. signup_date >= DATE '2024-01-01') without casting. Column Profiles list the null and distinct counts, ranges and the exact values of columns with few distinct values; use these exact values in filters instead of guessing them. Only a single read-only SELECT (optionally starting with WITH) over the tables in the schema is allowed; other statements, other tables, files and table functions other than unnest, range and generate_series are rejected.`
+
+func (s Server) ConvertUserQuestionToSQLAndRetrieveQueryResults(ctx context.Context, model string, stream *answerStream, history *chatHistory, metadata *messageMetadata, duckDB *chatDuckDB, userQuestion, tableName, schema, jsonPreview string) (*queryResult, string, error) {
 	sqlGenMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: "You are an AI assistant that generates SQL queries for DuckDB. The results of the query you produce will be fed back into OpenAI to answer the user's original question. Please ensure the result of the query is limited to a reasonable size to avoid hitting openAIs token limits. Let's aim for the results of the query to be 1000 or less openAI tokens"},
 	}
@@ -17,33 +23,48 @@ func (s Server) ConvertUserQuestionToSQLAndRetrieveQueryResults(duckDB *sql.DB,
 Table Name: %s
 Schema: %s
 
-Here is a small preview of the actual JSON data that was used to create the table:
 %s
 
+Here is a small preview of the actual JSON data that was used to create the table:
+%s
+%s
 Only Generate the SQL query as your output, without any explanations. I will directly take the response you generate as SQL and run it on the DuckDB database to get the data needed to answer the user's question. I'll feed the data from running the SQL back to an LLM to answer the original question'.
-`, userQuestion, tableName, schema, jsonPreview)
+`, userQuestion, tableName, schema, sqlQueryGuidance, jsonPreview, history.describe())
 
 	// Append the prompt to the message array
 	sqlGenMessages = append(sqlGenMessages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: prompt})
 
 	tries := 10
 	retrievedData := false
-	var results []map[string]interface{}
+	var results *queryResult
 	var finalSQLQuery string
 
 	for tries > 0 && !retrievedData {
-		// Generate the SQL query from OpenAI
-		sqlQuery, err := s.OpenAIChat(&sqlGenMessages)
+		attempt := 11 - tries
+
+		// Generate the SQL query from the model
+		stream.progress(stageGeneratingSQL, attempt, "")
+		generationStart := time.Now()
+		sqlQuery, err := s.chat(ctx, model, &sqlGenMessages)
+		metadata.Timings.SQLGenerationMs += elapsedMs(generationStart)
 		if err != nil {
-			return "", "", fmt.Errorf("failed to generate SQL query: %v", err)
+			return nil, "", fmt.Errorf("failed to generate SQL query: %v", err)
 		}
 		sqlQuery = cleanSQLGeneration(sqlQuery)
 
 		fmt.Printf("Generated SQL: %s\n", sqlQuery)
 
 		// Try to execute the SQL query
-		results, err = queryDuckDB(duckDB, sqlQuery)
+		stream.progress(stageRunningQuery, attempt, sqlQuery)
+		queryStart := time.Now()
+		results, err = s.runGeneratedQuery(ctx, duckDB, sqlQuery)
+		metadata.Timings.QueryMs += elapsedMs(queryStart)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, "", ctx.Err()
+			}
+			stream.progress(stageQueryFailed, attempt, err.Error())
+			metadata.FailedAttempts = append(metadata.FailedAttempts, failedQuery{SQL: sqlQuery, Error: err.Error()})
 			fmt.Printf("Error executing SQL query: %v\n", err)
 			errorMessage := fmt.Sprintf("The query you generated: '%s' resulted in the following error: %v\n Please fix the query.", sqlQuery, err)
 
@@ -57,13 +78,13 @@ Only Generate the SQL query as your output, without any explanations. I will dir
 	}
 
 	if !retrievedData {
-		return "", "", fmt.Errorf("failed to generate a valid SQL query after 10 attempts")
+		return nil, "", fmt.Errorf("failed to generate a valid SQL query after 10 attempts")
 	}
 
-	return genericResultsToString(results), finalSQLQuery, nil
+	return results, finalSQLQuery, nil
 }
 
-func (s Server) RetrieveRelevantInformation(db *sql.DB, userQuestion, tableName, schema, jsonPreview string) (string, string, error) {
+func (s Server) RetrieveRelevantInformation(ctx context.Context, model string, stream *answerStream, history *chatHistory, metadata *messageMetadata, db *chatDuckDB, userQuestion, tableName, schema, jsonPreview string) (*queryResult, string, error) {
 	sqlGenMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: "You are an AI assistant that generates SQL queries for DuckDB. The results of the query you produce will be fed back into OpenAI to answer the user's original question. Please ensure the result of the query is limited to a reasonable size to avoid hitting token limits. Let's aim for results that would take 1000 or less openAI tokens"},
 	}
@@ -72,33 +93,48 @@ func (s Server) RetrieveRelevantInformation(db *sql.DB, userQuestion, tableName,
 Table Name: %s
 Schema: %s
 
-Here is a small preview of the actual JSON data that was used to create the table:
 %s
 
+Here is a small preview of the actual JSON data that was used to create the table:
+%s
+%s
 Only Generate the SQL query as your output, without any explanations. I will directly take the response you generate as SQL and run it on the DuckDB database to get the data needed to answer the user's question. I'll feed the data from running the SQL back to an LLM to answer the original question'.
-`, userQuestion, tableName, schema, jsonPreview)
+`, userQuestion, tableName, schema, sqlQueryGuidance, jsonPreview, history.describe())
 
 	// Append the prompt to the message array
 	sqlGenMessages = append(sqlGenMessages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: prompt})
 
 	tries := 10
 	retrievedData := false
-	var results []map[string]interface{}
+	var results *queryResult
 	var finalSQLQuery string
 
 	for tries > 0 && !retrievedData {
-		// Generate the SQL query from OpenAI
-		sqlQuery, err := s.OpenAIChat(&sqlGenMessages)
+		attempt := 11 - tries
+
+		// Generate the SQL query from the model
+		stream.progress(stageGeneratingSQL, attempt, "")
+		generationStart := time.Now()
+		sqlQuery, err := s.chat(ctx, model, &sqlGenMessages)
+		metadata.Timings.SQLGenerationMs += elapsedMs(generationStart)
 		if err != nil {
-			return "", "", fmt.Errorf("Failed to generate SQL query: %v", err)
+			return nil, "", fmt.Errorf("Failed to generate SQL query: %v", err)
 		}
 		sqlQuery = cleanSQLGeneration(sqlQuery)
 
 		fmt.Printf("Generated SQL: %s\n", sqlQuery)
 
 		// Try to execute the SQL query
-		results, err = queryDuckDB(db, sqlQuery)
+		stream.progress(stageRunningQuery, attempt, sqlQuery)
+		queryStart := time.Now()
+		results, err = s.runGeneratedQuery(ctx, db, sqlQuery)
+		metadata.Timings.QueryMs += elapsedMs(queryStart)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, "", ctx.Err()
+			}
+			stream.progress(stageQueryFailed, attempt, err.Error())
+			metadata.FailedAttempts = append(metadata.FailedAttempts, failedQuery{SQL: sqlQuery, Error: err.Error()})
 			fmt.Printf("Error executing SQL query: %v\n", err)
 			errorMessage := fmt.Sprintf("The query you generated: '%s' resulted in the following error: %v\n Please fix the query.", sqlQuery, err)
 
@@ -112,32 +148,54 @@ Only Generate the SQL query as your output, without any explanations. I will dir
 
 	// If no valid results were retrieved after 10 tries, return an error
 	if !retrievedData {
-		return "", "", fmt.Errorf("failed to generate a valid SQL query after 10 attempts")
+		return nil, "", fmt.Errorf("failed to generate a valid SQL query after 10 attempts")
 	}
 
-	return genericResultsToString(results), finalSQLQuery, nil
+	return results, finalSQLQuery, nil
+}
+
+// runGeneratedQuery runs a generated query with the query timeout. A timeout is returned as an error the model
+// can act on, unless ctx itself was cancelled.
+func (s Server) runGeneratedQuery(ctx context.Context, duckDB *chatDuckDB, sqlQuery string) (*queryResult, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	results, err := duckDB.queryGenerated(queryCtx, sqlQuery, s.QueryLimits)
+	if err != nil && ctx.Err() == nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("the query took longer than %s and was cancelled, write a query that does less work", s.QueryTimeout)
+	}
+	return results, err
 }
 
-func (s Server) AnswerUserQuestionBasedOnSQlResults(results string, userQuestion string) (string, error) {
+func (s Server) AnswerUserQuestionBasedOnSQlResults(ctx context.Context, model string, stream *answerStream, history *chatHistory, results string, userQuestion string, columnMapping string, ingestionSummary string) (string, error) {
+	notes := ""
+	if columnMapping != "" {
+		notes = fmt.Sprintf("\nThe column names in the results were normalized from the user's JSON keys. When you refer to a field, use the user's original JSON key instead of the column name:\n%s", columnMapping)
+	}
+	if ingestionSummary != "" {
+		notes += fmt.Sprintf("\nNot all of the JSON could be loaded into the database, so the results may be incomplete. If it affects the answer (e.g. counts or totals), tell the user:\n%s", ingestionSummary)
+	}
+
 	answerMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: `You are an AI assistant that helps users answer questions by analyzing large JSON data. The system processes large JSON files by loading them into a database, executing queries, and retrieving results. Your role is to analyze the query results and answer the user's original question based on the data retrieved from the database.`},
 		{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf(`We received a large JSON from the user. We put the large JSON into a database and ran some queries. The following is the queries run and their results:
 %s
-
+%s
+%s
 Now, using this information, please answer the user's original question in a kind and friendly way. Do not mention the database or query in your response. Please answer as if you knew this information and are simply answering the users question:
-%s`, results, userQuestion)},
+%s`, results, notes, history.describe(), userQuestion)},
 	}
 
 	// Send the conversation to OpenAI and get the answer
-	answer, err := s.OpenAIChat(&answerMessages)
+	answer, err := s.chatStream(ctx, model, &answerMessages, stream)
 	if err != nil {
-		return "", fmt.Errorf("failed to get an answer from OpenAI: %v", err)
+		return "", fmt.Errorf("failed to get an answer from the model: %v", err)
 	}
 
 	return strings.TrimSpace(answer), nil
 }
 
-func (s Server) ValidateUserQuestion(userQuestion, schema, jsonPreview, jsonName string) (bool, error) {
+func (s Server) ValidateUserQuestion(ctx context.Context, model string, history *chatHistory, userQuestion, schema, jsonPreview, jsonName string) (bool, error) {
 	validationMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: `You are an AI assistant tasked with determining whether a user's question can be answered, inferred, or at least attempted using a given JSON file. You will be provided a schema and a preview of the data. Your task is to determine if the question relates to the data, either directly or indirectly, by matching key terms in the question to fields in the JSON or making logical inferences. If a term from the question does not match exactly, but there is a closely related field, you should still consider it as relevant and infer a connection.`},
 		{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf(`
@@ -149,7 +207,7 @@ Here is the schema of the DuckDB table the JSON has been loaded into:
 
 The user has asked the following question about the JSON:
 "%s"
-
+%s
 Please determine whether this question could be answered, inferred, or at least attempted based on the JSON. Even if the exact terms don't match, make logical inferences when possible (e.g., consider synonyms or related fields). The relevant data could be nested within fields like 'metadata', be sure to consider those details as well. 
 
 Err toward returning '1' unless the question is completely unrelated, and you cannot even make a reasonable attempt to answer it.
@@ -157,10 +215,10 @@ Err toward returning '1' unless the question is completely unrelated, and you ca
 If you are leaning 0, ask yourself, given the schema and preview JSON, can you come up with a SQL that could explore this data to potentially answer the users question? Only if you cannot even come up with a SQL query to explore the data to answer the question, only then return 0.
 
 Return '1' if you believe the question could be answered, inferred, attempted, or if you can even guess at the answer. Return '0' if you are certain the data is completely irrelevant to the question. If you return 0, please tell me the reason why.
-`, jsonName, jsonPreview, schema, userQuestion)},
+`, jsonName, jsonPreview, schema, userQuestion, history.describe())},
 	}
 
-	response, err := s.OpenAIChat(&validationMessages)
+	response, err := s.chat(ctx, model, &validationMessages)
 	if err != nil {
 		return false, fmt.Errorf("failed to validate user question with OpenAI: %v", err)
 	}
@@ -172,7 +230,7 @@ Return '1' if you believe the question could be answered, inferred, attempted, o
 	return response == "1", nil
 }
 
-func (s Server) ValidateUserQuestionBasedOnJson(userQuestion, jsonContent string) (bool, error) {
+func (s Server) ValidateUserQuestionBasedOnJson(ctx context.Context, model string, history *chatHistory, userQuestion, jsonContent string) (bool, error) {
 	validationMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: `You are an AI assistant tasked with determining whether a user's question can be answered, inferred, or at least attempted using a given JSON file. You will be provided the full JSON file. Your task is to determine if the question relates to the data, either directly or indirectly, by matching key terms in the question to fields in the JSON or making logical inferences. If a term from the question does not match exactly, but there is a closely related field, you should still consider it as relevant and infer a connection.`},
 		{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf(`
@@ -181,16 +239,16 @@ Here is the JSON file:
 
 The user has asked the following question about the JSON:
 "%s"
-
+%s
 Please determine whether this question could be answered, inferred, or at least attempted based on the JSON. Even if the exact terms don't match, make logical inferences when possible (e.g., consider synonyms or related fields).
 
 Err toward returning '1' unless the question is completely unrelated, and you cannot even make a reasonable attempt to answer it.
 
 Return '1' if you believe the question could be answered, inferred, attempted, or if you can even guess at the answer. Return '0' if you are certain the data is completely irrelevant to the question. If you return 0, please tell me the reason why.
-`, jsonContent, userQuestion)},
+`, jsonContent, userQuestion, history.describe())},
 	}
 
-	response, err := s.OpenAIChat(&validationMessages)
+	response, err := s.chat(ctx, model, &validationMessages)
 	if err != nil {
 		return false, fmt.Errorf("failed to validate user question with OpenAI: %v", err)
 	}
@@ -202,20 +260,21 @@ Return '1' if you believe the question could be answered, inferred, attempted, o
 	return response == "1", nil
 }
 
-func (s Server) AnswerUserQuestionBasedJson(JsonContent string, userQuestion string) (string, error) {
+func (s Server) AnswerUserQuestionBasedJson(ctx context.Context, model string, stream *answerStream, history *chatHistory, JsonContent string, userQuestion string) (string, error) {
 	answerMessages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: `You are an AI assistant that helps users answer questions by analyzing their JSON data. Your role is to analyze the Json given and answer the user's original question.`},
 		{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf(`Using the folowing JSON:
 %s
 
+%s
 Please answer the user's question in a kind and friendly way. The user asked the following question:
-%s`, JsonContent, userQuestion)},
+%s`, JsonContent, history.describe(), userQuestion)},
 	}
 
 	// Send the conversation to OpenAI and get the answer
-	answer, err := s.OpenAIChat(&answerMessages)
+	answer, err := s.chatStream(ctx, model, &answerMessages, stream)
 	if err != nil {
-		return "", fmt.Errorf("failed to get an answer from OpenAI: %v", err)
+		return "", fmt.Errorf("failed to get an answer from the model: %v", err)
 	}
 
 	return strings.TrimSpace(answer), nil
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAskPipelineWithScriptedModel(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+	jsonData := []interface{}{
+		map[string]interface{}{"name": "Widget", "price": float64(25)},
+		map[string]interface{}{"name": "Gadget", "price": float64(5)},
+	}
+	if _, err := LoadJSONIntoDuckDB(duckDB, jsonData, ingestModeNested, ""); err != nil {
+		t.Fatalf("Failed to load JSON: %v", err)
+	}
+	schema, err := loadTableSchema(duckDB)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	chatDB := &chatDuckDB{DB: duckDB, Schema: schema, Preview: `[{"name":"Widget","price":25}]`, release: func() {}}
+
+	model := &scriptedModel{Rules: []scriptRule{
+		{Contains: "Please determine whether this question", Response: "1"},
+		{Contains: "Please fix the query", Model: "sql-model", Response: "```sql\nSELECT name FROM json_data WHERE price > 10\n```"},
+		{Contains: "generate a SQL query", Model: "sql-model", Response: "SELECT title FROM json_data"},
+		{Contains: "please answer the user's original question", Model: "answer-model", Response: "The Widget costs more than 10."},
+	}}
+	s := Server{LLM: model, QueryTimeout: 10 * time.Second, QueryLimits: queryLimits{MaxRows: 10, MaxBytes: 8192}}
+	ctx := context.Background()
+	question := "Which products cost more than 10?"
+	history := &chatHistory{}
+
+	valid, err := s.ValidateUserQuestion(ctx, "validation-model", history, question, "name VARCHAR, price BIGINT", chatDB.Preview, "products.json")
+	if err != nil || !valid {
+		t.Fatalf("Got valid %v and error %v, want a valid question", valid, err)
+	}
+
+	metadata := newMessageMetadata()
+	results, query, err := s.ConvertUserQuestionToSQLAndRetrieveQueryResults(ctx, "sql-model", nil, history, metadata, chatDB, question, jsonTableName, "name VARCHAR, price BIGINT", chatDB.Preview)
+	if err != nil {
+		t.Fatalf("Failed to retrieve query results: %v", err)
+	}
+	if query != "SELECT name FROM json_data WHERE price > 10" || len(results.Rows) != 1 || results.Rows[0][0] != "Widget" {
+		t.Errorf("Got query %q with rows %v, want the fixed query returning Widget", query, results.Rows)
+	}
+	if len(metadata.FailedAttempts) != 1 || metadata.FailedAttempts[0].SQL != "SELECT title FROM json_data" {
+		t.Errorf("Got failed attempts %+v, want the first query", metadata.FailedAttempts)
+	}
+
+	answer, err := s.AnswerUserQuestionBasedOnSQlResults(ctx, "answer-model", nil, history, results.markdown(), question, "", "")
+	if err != nil || answer != "The Widget costs more than 10." {
+		t.Fatalf("Got answer %q and error %v", answer, err)
+	}
+
+	calls := model.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("Got %d model calls, want validation, two SQL generations and the answer", len(calls))
+	}
+	prompts := make([]string, len(calls))
+	for i, call := range calls {
+		prompts[i] = call[len(call)-1].Content
+	}
+	expected := []struct {
+		name     string
+		contains []string
+	}{
+		{"validation", []string{question, "products.json", "name VARCHAR, price BIGINT"}},
+		{"SQL generation", []string{question, "Table Name: json_data", sqlQueryGuidance, `"name":"Widget"`}},
+		{"SQL fix", []string{"SELECT title FROM json_data", "title"}},
+		{"answer", []string{question, "| name |", "| Widget |"}},
+	}
+	for i, prompt := range expected {
+		for _, text := range prompt.contains {
+			if !strings.Contains(prompts[i], text) {
+				t.Errorf("%s prompt does not contain %q:\n%s", prompt.name, text, prompts[i])
+			}
+		}
+	}
+
+	// The fix is asked in the same conversation, after the rejected query
+	if len(calls[2]) != len(calls[1])+2 {
+		t.Errorf("SQL fix sent %d messages, want the %d of the first generation followed by its answer and the error", len(calls[2]), len(calls[1]))
+	}
+}
+
+func TestFailedQuestionMetadataIsReturnedWithMessage(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+	jsonData := []interface{}{map[string]interface{}{"name": "Widget"}}
+	if _, err := LoadJSONIntoDuckDB(duckDB, jsonData, ingestModeNested, ""); err != nil {
+		t.Fatalf("Failed to load JSON: %v", err)
+	}
+	schema, err := loadTableSchema(duckDB)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	chatDB := &chatDuckDB{DB: duckDB, Schema: schema, Preview: `[{"name":"Widget"}]`, release: func() {}}
+
+	model := &scriptedModel{Rules: []scriptRule{{Response: "SELECT title FROM json_data"}}}
+	s := Server{LLM: model, QueryTimeout: 10 * time.Second, QueryLimits: queryLimits{MaxRows: 10, MaxBytes: 8192}}
+	start := time.Now()
+	metadata := newMessageMetadata()
+	_, _, err = s.ConvertUserQuestionToSQLAndRetrieveQueryResults(context.Background(), "sql-model", nil, &chatHistory{}, metadata, chatDB, "What is the title?", jsonTableName, "name VARCHAR", chatDB.Preview)
+	if err == nil {
+		t.Fatal("Got no error, want every attempt to fail")
+	}
+
+	message := &proto.Message{}
+	parseMessageMetadata(metadata.marshal(start)).addToProto(message)
+	if len(message.FailedAttempts) != 10 {
+		t.Fatalf("Got %d failed attempts, want all 10", len(message.FailedAttempts))
+	}
+	for _, attempt := range message.FailedAttempts {
+		if attempt.Sql != "SELECT title FROM json_data" || !strings.Contains(attempt.Error, "title") {
+			t.Errorf("Got failed attempt %+v, want the query with its binder error", attempt)
+		}
+	}
+	if message.Timings == nil || message.ResultTable != nil {
+		t.Errorf("Got timings %v and result %v, want timings without a result", message.Timings, message.ResultTable)
+	}
+}
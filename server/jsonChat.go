@@ -4,8 +4,6 @@ import (
 	"JsonAI/db"
 	"JsonAI/proto"
 	"context"
-	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	_ "github.com/marcboeker/go-duckdb"
@@ -19,6 +17,7 @@ import (
 
 const (
 	invalidQuestionResponse = "I cannot answer the query using the information from the file"
+	failedQueryResponse     = "I could not find a query that answers the question, please try rephrasing it"
 	maxTokenLimit           = 25000
 	maxResultTokenLimit     = 18000
 )
@@ -42,23 +41,24 @@ func (s Server) GetChat(ctx context.Context, in *proto.GetChat_Request) (*proto.
 		}
 	}
 
-	jChat, messages, err := db.GetChatByID(s.DB, in.ChatID)
+	jChat, messages, err := s.getOwnedChatWithMessages(in.UserID, in.ChatID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, status.Error(codes.NotFound, "Chat not found")
-		} else {
-			log.Printf("Failed to retrieve chat: %s", err)
-			return nil, status.Error(codes.Internal, "Failed to retrieve chat")
-		}
+		return nil, err
 	}
 
 	protoMessages := make([]*proto.Message, 0, len(messages))
 	for _, message := range messages {
-		protoMessages = append(protoMessages, &proto.Message{
+		protoMessage := &proto.Message{
 			Role:      message.Role,
 			Message:   message.Message,
 			CreatedAt: message.CreatedAt.Format(time.RFC3339),
-		})
+			Models:    parseStageModels(message.Models).toProto(),
+			Sql:       message.SQL,
+		}
+		if metadata := parseMessageMetadata(message.Metadata); metadata != nil {
+			metadata.addToProto(protoMessage)
+		}
+		protoMessages = append(protoMessages, protoMessage)
 	}
 
 	return &proto.GetChat_Response{
@@ -68,6 +68,12 @@ func (s Server) GetChat(ctx context.Context, in *proto.GetChat_Request) (*proto.
 			JsonName:     jChat.JSON,
 			MessageCount: int32(len(protoMessages)),
 			Messages:     protoMessages,
+			ExpiresAt:    formatExpiresAt(jChat.ExpiresAt),
+			Expired:      jChat.IsExpired(time.Now()),
+			Model:        jChat.LanguageModel,
+			Models:       parseStageModels(jChat.StageModels).toProto(),
+
+			IngestionReport: parseIngestionReport(jChat.IngestionReport).toProto(),
 		},
 	}, nil
 }
@@ -105,6 +111,10 @@ func (s Server) ListChats(ctx context.Context, in *proto.ListChats_Request) (*pr
 			UserID:       chat.UserID,
 			JsonName:     chat.JSON,
 			MessageCount: int32(messageCnt),
+			ExpiresAt:    formatExpiresAt(chat.ExpiresAt),
+			Expired:      chat.IsExpired(time.Now()),
+			Model:        chat.LanguageModel,
+			Models:       parseStageModels(chat.StageModels).toProto(),
 		})
 	}
 
@@ -114,6 +124,12 @@ func (s Server) ListChats(ctx context.Context, in *proto.ListChats_Request) (*pr
 }
 
 func (s Server) AskJsonAI(ctx context.Context, in *proto.AskJsonAI_Request) (*proto.AskJsonAI_Response, error) {
+	return s.askJsonAI(ctx, in, nil)
+}
+
+// askJsonAI answers the question of the request, sending its progress and the answer as it is generated to stream
+// when it is not nil.
+func (s Server) askJsonAI(ctx context.Context, in *proto.AskJsonAI_Request, stream *answerStream) (*proto.AskJsonAI_Response, error) {
 	if in.UserID == "" {
 		return nil, status.Error(codes.InvalidArgument, "UserID is required")
 	}
@@ -126,88 +142,92 @@ func (s Server) AskJsonAI(ctx context.Context, in *proto.AskJsonAI_Request) (*pr
 		return nil, status.Error(codes.InvalidArgument, "Please ask a question")
 	}
 
-	jaiChat, _, err := db.GetChatByID(s.DB, in.ChatID)
+	jaiChat, messages, err := s.getOwnedChatWithMessages(in.UserID, in.ChatID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, status.Error(codes.NotFound, "Chat not found")
-		} else {
-			log.Printf("Failed to retrieve chat: %s", err)
-			return nil, status.Error(codes.Internal, "Failed to retrieve chat")
-		}
+		return nil, err
 	}
 
-	if jaiChat.FileTokenEstimate < 2000 {
-		return s.handleSmallJson(ctx, in.Question, jaiChat)
-	}
-	return s.handleLargeJson(ctx, in.Question, jaiChat)
-}
-
-func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat *db.JaiChat) (*proto.AskJsonAI_Response, error) {
-	s3FileLocation := jChat.FileLocation
-	bucket, key, err := getBucketAndKeyFromS3URL(s3FileLocation)
-	if err != nil {
-		log.Printf("Failed to parse S3 URL: %s", err)
-		return nil, status.Error(codes.Internal, "Failed to parse S3 URL")
+	if jaiChat.IsExpired(time.Now()) {
+		return nil, status.Error(codes.FailedPrecondition, datasetExpiredResponse)
 	}
 
-	// Download the file from S3
-	jsonContent, err := s.DownloadFileFromS3(bucket, key)
+	models, err := s.selectModels(chatStageModels(jaiChat), allStages(in.Model), stageModelsFromProto(in.Models))
 	if err != nil {
-		log.Printf("Failed to download the file from S3: %s", err)
-		return nil, status.Error(codes.Internal, "Failed to download the file")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	jsonPreview := getJSONPreview(jsonContent, 5000)
+	// Follow-up questions refer to the earlier conversation, summarizing it is cheap enough for the validation model
+	history := s.loadChatHistory(ctx, models.Validation, jaiChat, messages, stream)
 
-	var jsonData interface{}
-	err = json.Unmarshal([]byte(jsonContent), &jsonData)
-	if err != nil {
-		log.Printf("Failed to unmarshal JSON: %s", err)
-		return nil, status.Error(codes.Internal, "Internal server error")
+	if jaiChat.FileTokenEstimate < 2000 {
+		return s.handleSmallJson(ctx, in.Question, jaiChat, history, models, stream)
 	}
+	return s.handleLargeJson(ctx, in.Question, jaiChat, history, models, stream)
+}
 
-	duckDB, err := sql.Open("duckdb", "")
-	if err != nil {
-		log.Printf("Failed to open DuckDB: %s", err)
-		return nil, status.Error(codes.Internal, "Internal server error")
+// respondWithoutQuery saves response as the answer to a question no query could be run for, together with the
+// models and metadata of the stages that ran, and returns it.
+func (s Server) respondWithoutQuery(ctx context.Context, jChat *db.JaiChat, response string, models stageModels, metadata *messageMetadata, start time.Time) (*proto.AskJsonAI_Response, error) {
+	systemMessage := &db.ChatMessages{
+		JaiChatID: jChat.UUID.ID,
+		Role:      openai.ChatMessageRoleAssistant,
+		Message:   response,
+		Models:    models.marshal(),
+		Metadata:  metadata.marshal(start),
+		Model: gorm.Model{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
 	}
-	defer func(duckDB *sql.DB) {
-		err := duckDB.Close()
-		if err != nil {
-			log.Printf("Failed to close DuckDB: %s", err)
-		}
-	}(duckDB)
-
-	// Create a new table in DuckDB
-	err = createTableFromJSON(duckDB, jsonData)
+	err := db.AddChatMessage(s.DB, systemMessage)
 	if err != nil {
-		log.Printf("Failed to create table from JSON: %s", err)
+		log.Printf("Failed to add system message: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
 
-	err = insertDataIntoDuckDB(duckDB, jsonData)
+	getChatResp, err := s.GetChat(ctx, &proto.GetChat_Request{
+		UserID: jChat.UserID,
+		ChatID: jChat.UUID.ID,
+	})
 	if err != nil {
-		log.Printf("Failed to insert data into DuckDB: %s", err)
+		log.Printf("Failed to get chat: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
-	tableName := "json_data"
 
-	schema, err := getTableSchema(duckDB, tableName)
+	return &proto.AskJsonAI_Response{
+		Answer: response,
+		Chat:   getChatResp.Chat,
+	}, nil
+}
+
+func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat *db.JaiChat, history *chatHistory, models stageModels, stream *answerStream) (*proto.AskJsonAI_Response, error) {
+	start := time.Now()
+	metadata := newMessageMetadata()
+
+	chatDB, err := s.openChatDuckDB(ctx, jChat)
 	if err != nil {
-		log.Printf("Failed to get schema for main table: %s", err)
+		log.Printf("Failed to open DuckDB for chat %s: %s", jChat.UUID.ID, err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
+	defer func(chatDB *chatDuckDB) {
+		err := chatDB.Close()
+		if err != nil {
+			log.Printf("Failed to close DuckDB: %s", err)
+		}
+	}(chatDB)
+
+	tableSchema := chatDB.Schema
+	tableName := tableSchema.Name
 
-	uniqueFields, err := extractUniqueFieldsFromJSONColumns(duckDB, tableName)
+	// Described once when the data was loaded, the database is only queried for the answer
+	schemaContext, err := s.chatSchemaContext(ctx, jChat, chatDB)
 	if err != nil {
-		log.Printf("Error extracting JSON fields: %v", err)
+		log.Printf("Failed to get schema context of chat %s: %s", jChat.UUID.ID, err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
-
-	totalSchema := "Table Schema:\n" + schema
-	if len(uniqueFields) > 0 {
-		totalSchema = totalSchema + "\n\nExpanded Fields from JSON Columns:\n" + uniqueFields
-	}
+	totalSchema := schemaContext.Schema
+	jsonPreview := schemaContext.Preview
+	ingestionSummary := schemaContext.IngestionSummary
 
 	// Output the schema
 	fmt.Println("Total schema:")
@@ -229,51 +249,33 @@ func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
 
-	isValidQuestion, err := s.ValidateUserQuestion(userQuestion, totalSchema, jsonPreview, jChat.JSON)
+	stream.progress(stageValidating, 0, "")
+	validationStart := time.Now()
+	isValidQuestion, err := s.ValidateUserQuestion(ctx, models.Validation, history, userQuestion, totalSchema, jsonPreview, jChat.JSON)
 	if err != nil {
 		log.Printf("Failed to validate user question: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
+	metadata.Timings.ValidationMs = elapsedMs(validationStart)
 
 	// If the user question is not valid, response with the invalid question message
 	if !isValidQuestion {
-		systemMessage := &db.ChatMessages{
-			JaiChatID: jChat.UUID.ID,
-			Role:      openai.ChatMessageRoleAssistant,
-			Message:   invalidQuestionResponse,
-			Model: gorm.Model{
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			},
-		}
-		err = db.AddChatMessage(s.DB, systemMessage)
-		if err != nil {
-			log.Printf("Failed to add system message: %s", err)
-			return nil, status.Error(codes.Internal, "Internal server error")
-		}
-
-		getChatResp, err := s.GetChat(ctx, &proto.GetChat_Request{
-			UserID: jChat.UserID,
-			ChatID: jChat.UUID.ID,
-		})
-		if err != nil {
-			log.Printf("Failed to get chat: %s", err)
-			return nil, status.Error(codes.Internal, "Internal server error")
-		}
-
-		return &proto.AskJsonAI_Response{
-			Answer: invalidQuestionResponse,
-			Chat:   getChatResp.Chat,
-		}, nil
+		return s.respondWithoutQuery(ctx, jChat, invalidQuestionResponse, stageModels{Validation: models.Validation}, metadata, start)
 	}
 
-	results, sqlQuery, err := s.ConvertUserQuestionToSQLAndRetrieveQueryResults(duckDB, userQuestion, tableName, totalSchema, jsonPreview)
+	results, sqlQuery, err := s.ConvertUserQuestionToSQLAndRetrieveQueryResults(ctx, models.SQL, stream, history, metadata, chatDB, userQuestion, tableName, totalSchema, jsonPreview)
 	if err != nil {
+		if ctx.Err() != nil {
+			// The client went away, the running query was cancelled with it
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
 		log.Printf("Failed to convert user question to SQL: %s", err)
-		return nil, status.Error(codes.Internal, "Internal server error")
+		// Keep the rejected and failed queries, they show why the question could not be answered
+		return s.respondWithoutQuery(ctx, jChat, failedQueryResponse, stageModels{Validation: models.Validation, SQL: models.SQL}, metadata, start)
 	}
 
-	resultsString := fmt.Sprintf("Query Run: %s\nQuery Results: %s\n", sqlQuery, results)
+	metadata.Result = results
+	resultsString := fmt.Sprintf("Query Run: %s\nQuery Results:\n%s", sqlQuery, results.markdown())
 
 	// Info: Uncomment the bottom lines to increase accuracy of the response but this will increase total time taken to respond and token usage.
 
@@ -282,13 +284,13 @@ func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat
 	//	log.Printf("Skipping second query because the first result is already too large. Estimated tokens: %d", result1EstimatedTokens)
 	//} else {
 	//	// Run the second query and append its result
-	//	results, sqlQuery, err = s.RetrieveRelevantInformation(duckDB, userQuestion, tableName, totalSchema, jsonPreview)
+	//	results, sqlQuery, err = s.RetrieveRelevantInformation(ctx, models.SQL, stream, history, metadata, chatDB, userQuestion, tableName, totalSchema, jsonPreview)
 	//	if err != nil {
 	//		log.Fatalf("Error running SQL query: %v", err)
 	//	}
 	//
 	//	// Include the query run information in the token count
-	//	result2String := fmt.Sprintf("\nQuery Run: %s\nQuery Results: %s\n", sqlQuery, results)
+	//	result2String := fmt.Sprintf("\nQuery Run: %s\nQuery Results:\n%s", sqlQuery, results.markdown())
 	//	result2EstimatedTokens := estimateTokenCount(result2String)
 	//
 	//	// If the combined token usage is under the limit, append the second result
@@ -299,16 +301,22 @@ func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat
 	//	}
 	//}
 
-	finalAnswer, err := s.AnswerUserQuestionBasedOnSQlResults(resultsString, userQuestion)
+	stream.progress(stageAnswering, 0, "")
+	answerStart := time.Now()
+	finalAnswer, err := s.AnswerUserQuestionBasedOnSQlResults(ctx, models.Answer, stream, history, resultsString, userQuestion, schemaContext.ColumnMapping, ingestionSummary)
 	if err != nil {
 		log.Printf("Failed to answer user question: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
+	metadata.Timings.AnswerMs = elapsedMs(answerStart)
 
 	systemMessage := &db.ChatMessages{
 		JaiChatID: jChat.UUID.ID,
 		Role:      openai.ChatMessageRoleAssistant,
 		Message:   finalAnswer,
+		Models:    models.marshal(),
+		SQL:       sqlQuery,
+		Metadata:  metadata.marshal(start),
 		Model: gorm.Model{
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -331,12 +339,17 @@ func (s Server) handleLargeJson(ctx context.Context, userQuestion string, jChat
 	}
 
 	return &proto.AskJsonAI_Response{
-		Answer: finalAnswer,
-		Chat:   getChatResp.Chat,
+		Answer:      finalAnswer,
+		Chat:        getChatResp.Chat,
+		ResultTable: results.toProto(),
+		Sql:         sqlQuery,
 	}, nil
 }
 
-func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat *db.JaiChat) (*proto.AskJsonAI_Response, error) {
+func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat *db.JaiChat, history *chatHistory, models stageModels, stream *answerStream) (*proto.AskJsonAI_Response, error) {
+	start := time.Now()
+	metadata := newMessageMetadata()
+
 	// Retrieve the JSON content
 	var jsonContent string
 	jCache, err := db.GetJsonFromCache(s.DB, jChat.UUID.ID)
@@ -348,7 +361,7 @@ func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat
 		}
 	} else {
 		s3FileLocation := jChat.FileLocation
-		bucket, key, err := getBucketAndKeyFromS3URL(s3FileLocation)
+		bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(s3FileLocation)
 		if err != nil {
 			log.Printf("Failed to parse S3 URL: %s", err)
 			return nil, status.Error(codes.Internal, "Failed to parse S3 URL")
@@ -386,11 +399,14 @@ func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
 
-	isValidQuestion, err := s.ValidateUserQuestionBasedOnJson(userQuestion, jsonContent)
+	stream.progress(stageValidating, 0, "")
+	validationStart := time.Now()
+	isValidQuestion, err := s.ValidateUserQuestionBasedOnJson(ctx, models.Validation, history, userQuestion, jsonContent)
 	if err != nil {
 		log.Printf("Failed to validate user question: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
+	metadata.Timings.ValidationMs = elapsedMs(validationStart)
 
 	// If the user question is not valid, response with the invalid question message
 	if !isValidQuestion {
@@ -398,6 +414,8 @@ func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat
 			JaiChatID: jChat.UUID.ID,
 			Role:      openai.ChatMessageRoleAssistant,
 			Message:   invalidQuestionResponse,
+			Models:    stageModels{Validation: models.Validation}.marshal(),
+			Metadata:  metadata.marshal(start),
 			Model: gorm.Model{
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
@@ -424,16 +442,21 @@ func (s Server) handleSmallJson(ctx context.Context, userQuestion string, jChat
 		}, nil
 	}
 
-	answer, err := s.AnswerUserQuestionBasedJson(jsonContent, userQuestion)
+	stream.progress(stageAnswering, 0, "")
+	answerStart := time.Now()
+	answer, err := s.AnswerUserQuestionBasedJson(ctx, models.Answer, stream, history, jsonContent, userQuestion)
 	if err != nil {
 		log.Printf("Failed to answer user question: %s", err)
 		return nil, status.Error(codes.Internal, "Internal server error")
 	}
+	metadata.Timings.AnswerMs = elapsedMs(answerStart)
 
 	systemMessage := &db.ChatMessages{
 		JaiChatID: jChat.UUID.ID,
 		Role:      openai.ChatMessageRoleAssistant,
 		Message:   answer,
+		Models:    stageModels{Validation: models.Validation, Answer: models.Answer}.marshal(),
+		Metadata:  metadata.marshal(start),
 		Model: gorm.Model{
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
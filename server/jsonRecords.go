@@ -0,0 +1,267 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Column used for arrays of scalars
+	scalarValueKey = "value"
+
+	// Number of rows checked when deciding whether the first row of an array of arrays is a header
+	headerSampleRows = 100
+)
+
+// jsonDocument is an uploaded JSON split into the records that become the rows of json_data and the rest of the
+// document.
+type jsonDocument struct {
+	Records  []interface{}
+	Path     string                 // JSON Pointer of the records, empty when the document itself holds them
+	Metadata map[string]interface{} // Fields next to the records, nil when there are none
+}
+
+// extractRecords finds the records in the JSON. With a recordPath (a JSON Pointer such as /data/items) the
+// records are read from there, otherwise the largest array of objects in the document is used, so wrapper
+// objects like {"meta": {...}, "data": [...]} are loaded as one row per element of data.
+func extractRecords(jsonData interface{}, recordPath string) (*jsonDocument, error) {
+	var tokens []string
+	if recordPath != "" {
+		var err error
+		tokens, err = parseJSONPointer(recordPath)
+		if err != nil {
+			return nil, err
+		}
+	} else if root, ok := jsonData.(map[string]interface{}); ok {
+		tokens, _ = findLargestObjectArray(root, nil)
+	}
+
+	target, err := resolveJSONPointer(jsonData, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &jsonDocument{Path: formatJSONPointer(tokens)}
+	switch data := target.(type) {
+	case []interface{}:
+		doc.Records = normalizeRecords(data)
+	case map[string]interface{}:
+		// If it's a single JSON object
+		doc.Records = []interface{}{data}
+	default:
+		if recordPath == "" {
+			return nil, fmt.Errorf("the JSON is %s, not an array or object", jsonKindName(target))
+		}
+		return nil, fmt.Errorf("%s points to %s, not an array or object", recordPath, jsonKindName(target))
+	}
+
+	if len(tokens) > 0 {
+		if metadata, ok := withoutPath(jsonData, tokens); ok && len(metadata) > 0 {
+			doc.Metadata = metadata
+		}
+	}
+	return doc, nil
+}
+
+// findLargestObjectArray returns the path of the array with the most objects, searching nested objects but not
+// the elements of arrays.
+func findLargestObjectArray(object map[string]interface{}, prefix []string) ([]string, int) {
+	var best []string
+	bestCount := 0
+	for _, key := range sortedKeys(object) {
+		path := append(append([]string{}, prefix...), key)
+		switch v := object[key].(type) {
+		case []interface{}:
+			count := 0
+			for _, item := range v {
+				if _, ok := item.(map[string]interface{}); ok {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = path, count
+			}
+		case map[string]interface{}:
+			if nested, count := findLargestObjectArray(v, path); count > bestCount {
+				best, bestCount = nested, count
+			}
+		}
+	}
+	return best, bestCount
+}
+
+// normalizeRecords turns arrays that do not hold objects into objects: scalars become {"value": ...} and arrays
+// of arrays become one object per row, keyed by the header row if there is one, otherwise col_1, col_2, ...
+func normalizeRecords(items []interface{}) []interface{} {
+	objects, arrays, scalars := 0, 0, 0
+	for _, item := range items {
+		switch item.(type) {
+		case nil:
+		case map[string]interface{}:
+			objects++
+		case []interface{}:
+			arrays++
+		default:
+			scalars++
+		}
+	}
+
+	switch {
+	case objects >= arrays && objects >= scalars:
+		return items
+	case arrays > scalars:
+		return rowsToRecords(items)
+	default:
+		records := make([]interface{}, len(items))
+		for i, item := range items {
+			if _, ok := item.(map[string]interface{}); ok || item == nil {
+				records[i] = item
+				continue
+			}
+			records[i] = map[string]interface{}{scalarValueKey: item}
+		}
+		return records
+	}
+}
+
+func rowsToRecords(items []interface{}) []interface{} {
+	header := detectHeader(items)
+	if header != nil {
+		items = items[1:]
+	}
+
+	records := make([]interface{}, len(items))
+	for i, item := range items {
+		row, ok := item.([]interface{})
+		if !ok {
+			// Left as is, it is reported as skipped
+			records[i] = item
+			continue
+		}
+
+		record := make(map[string]interface{}, len(row))
+		for j, value := range row {
+			if j < len(header) {
+				record[header[j]] = value
+			} else {
+				record[fmt.Sprintf("col_%d", j+1)] = value
+			}
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// detectHeader returns the first row as column names if it looks like a header: unique non-empty strings where
+// the following rows hold other values.
+func detectHeader(items []interface{}) []string {
+	if len(items) < 2 {
+		return nil
+	}
+	first, ok := items[0].([]interface{})
+	if !ok || len(first) == 0 {
+		return nil
+	}
+
+	header := make([]string, len(first))
+	seen := make(map[string]bool, len(first))
+	for i, value := range first {
+		name, ok := value.(string)
+		if !ok || name == "" || seen[name] {
+			return nil
+		}
+		header[i] = name
+		seen[name] = true
+	}
+
+	for _, item := range items[1:min(len(items), headerSampleRows+1)] {
+		row, ok := item.([]interface{})
+		if !ok {
+			continue
+		}
+		for i, value := range row {
+			// A header name repeated in its own column is data, not a header
+			if i < len(header) && value == header[i] {
+				return nil
+			}
+		}
+	}
+	return header
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func formatJSONPointer(tokens []string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return pointer.String()
+}
+
+func resolveJSONPointer(value interface{}, tokens []string) (interface{}, error) {
+	for i, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found in the JSON", formatJSONPointer(tokens[:i+1]))
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("%s not found in the JSON", formatJSONPointer(tokens[:i+1]))
+			}
+			value = v[index]
+		default:
+			return nil, fmt.Errorf("%s not found in the JSON", formatJSONPointer(tokens[:i+1]))
+		}
+	}
+	return value, nil
+}
+
+// withoutPath returns a copy of the objects along the path with the value at the path removed. It returns false
+// when the path goes through an array.
+func withoutPath(value interface{}, tokens []string) (map[string]interface{}, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	rest := make(map[string]interface{}, len(object))
+	for key, v := range object {
+		rest[key] = v
+	}
+
+	if len(tokens) == 1 {
+		delete(rest, tokens[0])
+		return rest, true
+	}
+
+	nested, ok := withoutPath(rest[tokens[0]], tokens[1:])
+	if !ok {
+		return nil, false
+	}
+	if len(nested) == 0 {
+		delete(rest, tokens[0])
+	} else {
+		rest[tokens[0]] = nested
+	}
+	return rest, true
+}
@@ -0,0 +1,563 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	jsonTableName = "json_data"
+
+	// Holds the rest of the document when the records were found inside a wrapper object
+	metadataTableName = "json_metadata"
+
+	// In nested mode arrays stay LIST columns, in shred mode arrays of objects are split into child tables
+	ingestModeNested = "nested"
+	ingestModeShred  = "shred"
+
+	rowIDColumn    = "_row_id"
+	parentIDColumn = "_parent_id"
+
+	// DuckDB tables describing the tables created for the JSON and mapping every column back to its JSON key
+	tableMetadataTable = "json_tables"
+	columnMappingTable = "json_columns"
+)
+
+// sqlKeywords are reserved words that get a trailing underscore when a JSON key normalizes to one of them.
+var sqlKeywords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true, "asc": true,
+	"asymmetric": true, "between": true, "both": true, "by": true, "case": true, "cast": true, "check": true,
+	"collate": true, "column": true, "constraint": true, "create": true, "cross": true, "current_date": true,
+	"current_time": true, "current_timestamp": true, "current_user": true, "default": true, "deferrable": true,
+	"delete": true, "desc": true, "describe": true, "distinct": true, "do": true, "else": true, "end": true,
+	"except": true, "exists": true, "false": true, "fetch": true, "for": true, "foreign": true, "from": true,
+	"full": true, "grant": true, "group": true, "having": true, "in": true, "initially": true, "inner": true,
+	"insert": true, "intersect": true, "into": true, "is": true, "join": true, "key": true, "lambda": true,
+	"lateral": true, "leading": true, "left": true, "like": true, "limit": true, "localtime": true,
+	"localtimestamp": true, "natural": true, "not": true, "null": true, "offset": true, "on": true, "only": true,
+	"or": true, "order": true, "outer": true, "pivot": true, "placing": true, "primary": true, "qualify": true,
+	"references": true, "returning": true, "right": true, "select": true, "session_user": true, "show": true,
+	"some": true, "summarize": true, "symmetric": true, "table": true, "then": true, "to": true, "trailing": true,
+	"true": true, "union": true, "unique": true, "unpivot": true, "update": true, "user": true, "using": true,
+	"values": true, "variadic": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// DuckDB column types inferred from JSON values. When records disagree on the type of a key the column is
+// widened: BIGINT -> DOUBLE -> VARCHAR. Nested objects become STRUCTs and arrays become LISTs; nested values
+// whose shape differs between records fall back to the JSON type. Strings may be detected as DATE, TIMESTAMP
+// or DECIMAL, see typeDetection.go.
+const (
+	typeUnknown = ""
+	typeBoolean = "BOOLEAN"
+	typeBigInt  = "BIGINT"
+	typeDouble  = "DOUBLE"
+	typeVarchar = "VARCHAR"
+	typeStruct  = "STRUCT"
+	typeList    = "LIST"
+	typeJSON    = "JSON"
+)
+
+type jsonType struct {
+	Kind   string
+	Fields []*structField          // Set for STRUCT
+	Elem   *jsonType               // Set for LIST
+	index  map[string]*structField // Fields by JSON key
+	names  map[string]bool         // Lower case field names in use, DuckDB field names are case-insensitive
+
+	Format        string  // Format of the JSON values of detected types, e.g. epoch milliseconds
+	IntegerDigits int     // Set for DECIMAL
+	Scale         int     // Set for DECIMAL
+	Min, Max      float64 // Range of BIGINT values
+}
+
+type structField struct {
+	Name string // Field name, the JSON key made unique within the STRUCT
+	Key  string // Original JSON key
+	Type *jsonType
+}
+
+type tableColumn struct {
+	Name string // Normalized column name
+	Key  string // Original JSON key
+	Path string // Path of the key from the root record, e.g. items[].sku
+	Type *jsonType
+
+	detectedTypes []string // Read from json_columns when the schema is loaded without types
+}
+
+// tableSchema is the union of the keys of all records, in the order they were first seen.
+type tableSchema struct {
+	Name    string
+	Path    string // Path of the records from the root record, empty for the root table
+	Columns []*tableColumn
+	index   map[string]*tableColumn // Columns by JSON key
+	names   map[string]bool         // Column names in use
+
+	// Set when arrays of objects are shredded into child tables
+	RowIDs    bool
+	Parent    *tableSchema
+	ParentKey string // Key of the array in the parent record
+	Children  []*tableSchema
+	nextRowID int64
+
+	Metadata *tableSchema // Set on the root table when the document has fields next to the records
+}
+
+func newTableSchema(name string) *tableSchema {
+	return &tableSchema{
+		Name:  name,
+		index: make(map[string]*tableColumn),
+		names: map[string]bool{rowIDColumn: true, parentIDColumn: true},
+	}
+}
+
+func inferTableSchema(name string, records []map[string]interface{}) *tableSchema {
+	schema := newTableSchema(name)
+	for _, record := range records {
+		for _, key := range sortedKeys(record) {
+			column, ok := schema.index[key]
+			if !ok {
+				column = schema.addColumn(key, &jsonType{})
+			}
+			column.Type = widenType(column.Type, determineFieldType(record[key]))
+		}
+	}
+
+	for _, column := range schema.Columns {
+		column.Type.finalize()
+		detectEpochMillis(column.Name, column.Type)
+	}
+	return schema
+}
+
+// addColumn adds a column for the JSON key. The column name is normalized and made unique within the table,
+// so keys that only differ in case or punctuation do not collide.
+func (t *tableSchema) addColumn(key string, columnType *jsonType) *tableColumn {
+	name := normalizeIdentifier(key)
+	for i := 2; t.names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", normalizeIdentifier(key), i)
+	}
+	t.names[name] = true
+
+	path := key
+	if t.Path != "" {
+		path = t.Path + "." + key
+	}
+
+	column := &tableColumn{Name: name, Key: key, Path: path, Type: columnType}
+	t.index[key] = column
+	t.Columns = append(t.Columns, column)
+	return column
+}
+
+// shred moves every column holding an array of objects into a child table with one row per array element.
+// Rows are linked through generated row ids.
+func (t *tableSchema) shred() {
+	t.RowIDs = true
+
+	columns := make([]*tableColumn, 0, len(t.Columns))
+	for _, column := range t.Columns {
+		if column.Type.Kind != typeList || column.Type.Elem.Kind != typeStruct {
+			columns = append(columns, column)
+			continue
+		}
+
+		child := newTableSchema(t.Name + "__" + column.Name)
+		child.Path = column.Path + "[]"
+		child.Parent = t
+		child.ParentKey = column.Key
+		for _, field := range column.Type.Elem.Fields {
+			child.addColumn(field.Key, field.Type)
+		}
+		child.shred()
+
+		t.Children = append(t.Children, child)
+		delete(t.index, column.Key)
+	}
+	t.Columns = columns
+}
+
+// tables returns the table and all of its child tables, parents first, followed by the metadata table.
+func (t *tableSchema) tables() []*tableSchema {
+	tables := []*tableSchema{t}
+	for _, child := range t.Children {
+		tables = append(tables, child.tables()...)
+	}
+	if t.Metadata != nil {
+		tables = append(tables, t.Metadata)
+	}
+	return tables
+}
+
+func (t *tableSchema) child(key string) *tableSchema {
+	for _, child := range t.Children {
+		if child.ParentKey == key {
+			return child
+		}
+	}
+	return nil
+}
+
+// describeRelationships explains how child tables join to their parents for the SQL prompt.
+func (t *tableSchema) describeRelationships() string {
+	var result strings.Builder
+	for _, table := range t.tables() {
+		if table == t.Metadata {
+			result.WriteString(fmt.Sprintf("- %s has a single row with the fields of the JSON document next to the records in %s, it cannot be joined\n", table.Name, t.Name))
+			continue
+		}
+		if table.Parent == nil {
+			continue
+		}
+		result.WriteString(fmt.Sprintf("- %s holds the elements of the \"%s\" array of %s, one row per element. Join them with %s.%s = %s.%s\n",
+			table.Name, table.ParentKey, table.Parent.Name, table.Name, parentIDColumn, table.Parent.Name, rowIDColumn))
+	}
+	return result.String()
+}
+
+// describeDetectedTypes lists the columns whose type was detected from the format of their values.
+func (t *tableSchema) describeDetectedTypes() string {
+	var result strings.Builder
+	for _, table := range t.tables() {
+		for _, column := range table.Columns {
+			for _, line := range column.describeDetectedTypes() {
+				result.WriteString(fmt.Sprintf("- %s.%s\n", table.Name, line))
+			}
+		}
+	}
+	return result.String()
+}
+
+func (c *tableColumn) describeDetectedTypes() []string {
+	if c.Type == nil {
+		return c.detectedTypes
+	}
+	return describeDetectedTypes(c.Name, c.Type)
+}
+
+// describeColumnMapping lists the columns and nested fields whose name differs from the JSON key they hold.
+func (t *tableSchema) describeColumnMapping() string {
+	var result strings.Builder
+	for _, table := range t.tables() {
+		for _, column := range table.Columns {
+			if column.Name != column.Path {
+				result.WriteString(fmt.Sprintf("- %s.%s holds the JSON key \"%s\"\n", table.Name, column.Name, column.Path))
+			}
+			for _, field := range column.renamedFields() {
+				result.WriteString(fmt.Sprintf("- %s.%s holds the JSON key \"%s\"\n", table.Name, field.Name, field.Key))
+			}
+		}
+	}
+	return result.String()
+}
+
+// renamedFields returns the nested fields of the column that were renamed to keep their names unique, with the
+// paths of the field and of the JSON key.
+func (c *tableColumn) renamedFields() []*structField {
+	if c.Type == nil {
+		return nil
+	}
+	return renamedFields(c.Name, c.Path, c.Type)
+}
+
+func renamedFields(path, keyPath string, t *jsonType) []*structField {
+	switch t.Kind {
+	case typeStruct:
+		var fields []*structField
+		for _, field := range t.Fields {
+			fieldPath := path + "." + field.Name
+			fieldKeyPath := keyPath + "." + field.Key
+			if field.Name != field.Key {
+				fields = append(fields, &structField{Name: fieldPath, Key: fieldKeyPath, Type: field.Type})
+			}
+			fields = append(fields, renamedFields(fieldPath, fieldKeyPath, field.Type)...)
+		}
+		return fields
+	case typeList:
+		return renamedFields(path+"[]", keyPath+"[]", t.Elem)
+	}
+	return nil
+}
+
+// normalizeIdentifier turns a JSON key into a lower snake_case identifier that is safe to use in SQL.
+func normalizeIdentifier(key string) string {
+	var name strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			// Split camelCase keys into words
+			if i > 0 && ((runes[i-1] >= 'a' && runes[i-1] <= 'z') || (runes[i-1] >= '0' && runes[i-1] <= '9')) {
+				name.WriteRune('_')
+			}
+			name.WriteRune(r - 'A' + 'a')
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			name.WriteRune(r)
+		default:
+			name.WriteRune('_')
+		}
+	}
+
+	// Collapse runs of underscores, the double underscore separates parent and child table names
+	normalized := name.String()
+	for strings.Contains(normalized, "__") {
+		normalized = strings.ReplaceAll(normalized, "__", "_")
+	}
+	normalized = strings.Trim(normalized, "_")
+	if normalized == "" {
+		// Keys without letters or digits, like "" or "$"
+		normalized = "field"
+	}
+
+	switch {
+	case normalized[0] >= '0' && normalized[0] <= '9':
+		return "col_" + normalized
+	case sqlKeywords[normalized]:
+		return normalized + "_"
+	}
+	return normalized
+}
+
+func isValidIngestMode(mode string) bool {
+	return mode == ingestModeNested || mode == ingestModeShred
+}
+
+func determineFieldType(value interface{}) *jsonType {
+	switch v := value.(type) {
+	case nil:
+		return &jsonType{Kind: typeUnknown}
+	case bool:
+		return &jsonType{Kind: typeBoolean}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return &jsonType{Kind: typeBigInt, Min: v, Max: v}
+		}
+		return &jsonType{Kind: typeDouble}
+	case string:
+		return detectStringType(v)
+	case map[string]interface{}:
+		t := &jsonType{Kind: typeStruct, index: make(map[string]*structField), names: make(map[string]bool)}
+		for _, key := range sortedKeys(v) {
+			t.addField(key, determineFieldType(v[key]))
+		}
+		return t
+	case []interface{}:
+		t := &jsonType{Kind: typeList, Elem: &jsonType{Kind: typeUnknown}}
+		for _, item := range v {
+			t.Elem = widenType(t.Elem, determineFieldType(item))
+		}
+		return t
+	default:
+		return &jsonType{Kind: typeVarchar}
+	}
+}
+
+// addField adds a field for the JSON key. Keys that only differ in case get a numbered name, like columns.
+func (t *jsonType) addField(key string, fieldType *jsonType) {
+	name := key
+	for i := 2; t.names[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s_%d", key, i)
+	}
+	t.names[strings.ToLower(name)] = true
+
+	field := &structField{Name: name, Key: key, Type: fieldType}
+	t.index[key] = field
+	t.Fields = append(t.Fields, field)
+}
+
+func (t *jsonType) isNested() bool {
+	return t.Kind == typeStruct || t.Kind == typeList || t.Kind == typeJSON
+}
+
+// widenType returns a type that can hold values of both types. It may modify current.
+func widenType(current, next *jsonType) *jsonType {
+	switch {
+	case next.Kind == typeUnknown:
+		return current
+	case current.Kind == typeUnknown:
+		return next
+	case current.Kind == typeStruct && next.Kind == typeStruct:
+		for _, field := range next.Fields {
+			if existing, ok := current.index[field.Key]; ok {
+				existing.Type = widenType(existing.Type, field.Type)
+			} else {
+				current.addField(field.Key, field.Type)
+			}
+		}
+		return current
+	case current.Kind == typeList && next.Kind == typeList:
+		current.Elem = widenType(current.Elem, next.Elem)
+		return current
+	case current.isNested() || next.isNested():
+		return &jsonType{Kind: typeJSON}
+	case current.Kind == next.Kind:
+		return widenSameKind(current, next)
+	case (current.Kind == typeBigInt && next.Kind == typeDouble) || (current.Kind == typeDouble && next.Kind == typeBigInt):
+		return &jsonType{Kind: typeDouble}
+	default:
+		return widenDetectedType(current, next)
+	}
+}
+
+// finalize resolves types that were never observed with a value.
+func (t *jsonType) finalize() {
+	switch t.Kind {
+	case typeUnknown:
+		t.Kind = typeVarchar
+	case typeStruct:
+		if len(t.Fields) == 0 {
+			// DuckDB has no empty STRUCT
+			t.Kind = typeJSON
+			return
+		}
+		for _, field := range t.Fields {
+			field.Type.finalize()
+		}
+	case typeList:
+		t.Elem.finalize()
+	}
+}
+
+// SQL returns the DuckDB type, including the full nested type of STRUCT and LIST columns.
+func (t *jsonType) SQL() string {
+	switch t.Kind {
+	case typeStruct:
+		fields := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			fields = append(fields, fmt.Sprintf("%s %s", quoteIdentifier(field.Name), field.Type.SQL()))
+		}
+		return fmt.Sprintf("STRUCT(%s)", strings.Join(fields, ", "))
+	case typeList:
+		return t.Elem.SQL() + "[]"
+	case typeDecimal:
+		return fmt.Sprintf("DECIMAL(%d,%d)", t.IntegerDigits+t.Scale, t.Scale)
+	default:
+		return t.Kind
+	}
+}
+
+// appenderValue converts a JSON value into the Go value DuckDB's appender expects for the column type. STRUCT
+// values need every field of the type, missing fields are appended as null.
+func appenderValue(t *jsonType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t.Kind {
+	case typeStruct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object, got %T", value)
+		}
+		converted := make(map[string]interface{}, len(t.Fields))
+		for _, field := range t.Fields {
+			fieldValue, err := appenderValue(field.Type, object[field.Key])
+			if err != nil {
+				return nil, err
+			}
+			converted[field.Name] = fieldValue
+		}
+		return converted, nil
+	case typeList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected array, got %T", value)
+		}
+		converted := make([]interface{}, len(items))
+		for i, item := range items {
+			itemValue, err := appenderValue(t.Elem, item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = itemValue
+		}
+		return converted, nil
+	case typeJSON:
+		serializedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(serializedValue), nil
+	default:
+		return coerceValue(t, value)
+	}
+}
+
+// coerceValue converts a scalar JSON value into the Go value DuckDB expects for the column type.
+func coerceValue(t *jsonType, value interface{}) (interface{}, error) {
+	switch t.Kind {
+	case typeBigInt:
+		if v, ok := value.(float64); ok {
+			return int64(v), nil
+		}
+	case typeDate:
+		if v, ok := value.(string); ok {
+			return time.Parse(dateLayout, v)
+		}
+	case typeTimestamp:
+		switch v := value.(type) {
+		case float64:
+			return time.UnixMilli(int64(v)).UTC(), nil
+		case string:
+			return parseTimestamp(v)
+		}
+	case typeDecimal:
+		if v, ok := value.(string); ok {
+			return decimalValue(t, v)
+		}
+	case typeVarchar:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		default:
+			serializedValue, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(serializedValue), nil
+		}
+	}
+	return value, nil
+}
+
+// jsonKindName names the kind of a JSON value for messages shown to users.
+func jsonKindName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadJSONDedupesNestedFieldNames(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+
+	jsonData := []interface{}{map[string]interface{}{"a": map[string]interface{}{"Id": float64(1), "id": float64(2)}}}
+	report, err := LoadJSONIntoDuckDB(duckDB, jsonData, ingestModeNested, "")
+	if err != nil {
+		t.Fatalf("Failed to load JSON: %v", err)
+	}
+
+	var first, second int64
+	if err := duckDB.QueryRow(`SELECT a."Id", a.id_2 FROM json_data`).Scan(&first, &second); err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	if first != 1 || second != 2 {
+		t.Errorf("Got a.Id = %d and a.id_2 = %d, want 1 and 2", first, second)
+	}
+
+	found := false
+	for _, column := range report.Tables[0].RenamedFields {
+		if column.Name == "a.id_2" && column.JSONKey == "a.id" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Ingestion report does not map a.id_2 to the JSON key a.id: %+v", report.Tables[0].RenamedFields)
+	}
+
+	schema := inferTableSchema(jsonTableName, []map[string]interface{}{jsonData[0].(map[string]interface{})})
+	if mapping := schema.describeColumnMapping(); !strings.Contains(mapping, `json_data.a.id_2 holds the JSON key "a.id"`) {
+		t.Errorf("Column mapping does not describe the renamed field: %q", mapping)
+	}
+}
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"first name", "first_name"},
+		{"userId", "user_id"},
+		{"1st", "col_1st"},
+		{"order", "order_"},
+		{"column", "column_"},
+		{"", "field"},
+		{"$", "field"},
+		{"__", "field"},
+	}
+
+	for _, test := range tests {
+		got := normalizeIdentifier(test.key)
+		if got != test.want {
+			t.Errorf("normalizeIdentifier(%q) = %q, want %q", test.key, got, test.want)
+		}
+		if sqlKeywords[got] {
+			t.Errorf("normalizeIdentifier(%q) = %q is a keyword", test.key, got)
+		}
+	}
+}
+
+func TestLoadJSONWithKeysWithoutLetters(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+
+	jsonData := []interface{}{map[string]interface{}{"": float64(1), "$": float64(2)}}
+	if _, err := LoadJSONIntoDuckDB(duckDB, jsonData, ingestModeNested, ""); err != nil {
+		t.Fatalf("Failed to load JSON: %v", err)
+	}
+
+	var first, second int64
+	if err := duckDB.QueryRow("SELECT field, field_2 FROM json_data").Scan(&first, &second); err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	if first+second != 3 {
+		t.Errorf("Got field = %d and field_2 = %d, want the values 1 and 2", first, second)
+	}
+}
+
+func TestBuildChatDuckDBWithNestedKeysDifferingInCase(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		ingestMode string
+		renamed    string
+	}{
+		{"struct", `[{"id":1,"meta":{"Name":"a","name":"b"}},{"id":2,"meta":{"Name":"c","name":"d"}}]`, ingestModeNested, "meta.name_2"},
+		{"list of structs", `[{"tags":[{"Name":"a","name":"b"}]}]`, ingestModeNested, "tags[].name_2"},
+		{"child table", `[{"tags":[{"Name":"a","name":"b"}]}]`, ingestModeShred, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "chat.duckdb")
+			built, err := buildChatDuckDB(path, []byte(test.json), test.ingestMode, "")
+			if err != nil {
+				t.Fatalf("Failed to build chat database: %v", err)
+			}
+
+			var renamed []string
+			for _, table := range built.Report.Tables {
+				for _, column := range table.Columns {
+					if strings.ContainsAny(column.Name, ".[") {
+						t.Errorf("Report lists the field %s as a column of %s", column.Name, table.Name)
+					}
+				}
+				for _, field := range table.RenamedFields {
+					renamed = append(renamed, field.Name)
+				}
+			}
+			if test.renamed != "" && (len(renamed) != 1 || renamed[0] != test.renamed) {
+				t.Errorf("Got renamed fields %v, want %s", renamed, test.renamed)
+			}
+			if len(built.Profile.Tables) != len(built.Report.Tables) {
+				t.Errorf("Got %d profiled tables, want %d", len(built.Profile.Tables), len(built.Report.Tables))
+			}
+		})
+	}
+}
@@ -37,6 +37,7 @@ func (s Server) handleJsonUpload(w http.ResponseWriter, r *http.Request) {
 		} else {
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
+		return
 	}
 
 	// Parse and validate the file upload
@@ -47,6 +48,36 @@ func (s Server) handleJsonUpload(w http.ResponseWriter, r *http.Request) {
 	}
 	defer closeFile(file)
 
+	ingestMode := r.FormValue("ingestMode")
+	if ingestMode == "" {
+		ingestMode = s.IngestMode
+	}
+	if !isValidIngestMode(ingestMode) {
+		http.Error(w, fmt.Sprintf("ingestMode must be %s or %s", ingestModeNested, ingestModeShred), http.StatusBadRequest)
+		return
+	}
+
+	// Replaces the configured model of every stage for all questions of the chat, the models of single stages
+	// take precedence over it
+	model := r.FormValue("model")
+	if err := s.validateModel(model); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	models := stageModels{
+		Validation: r.FormValue("modelValidation"),
+		SQL:        r.FormValue("modelSql"),
+		Answer:     r.FormValue("modelAnswer"),
+	}
+	if err := s.validateStageModels(models); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	storedModels := ""
+	if models != (stageModels{}) {
+		storedModels = models.marshal()
+	}
+
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		logErrorAndRespond(w, "Error reading file", err, http.StatusInternalServerError)
@@ -58,8 +89,28 @@ func (s Server) handleJsonUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordPath := r.FormValue("recordPath")
+	if err := validateRecordPath(fileBytes, recordPath); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid recordPath: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Clients can pass their own uploadID to poll GetUploadStatus while the request is running. It is scoped to
+	// the user and must not be in use by another of their uploads.
+	uploadID := r.FormValue("uploadID")
+	if uploadID == "" {
+		uploadID = uuid.New().String()
+	}
+	upload := uploadKey{userID: userID, uploadID: uploadID}
+	if err := s.Uploads.start(upload); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.Header().Set("X-Upload-ID", uploadID)
+
 	filePath := filepath.Join("./tmp", handler.Filename)
 	if err := saveFileToDisk(filePath, fileBytes); err != nil {
+		s.Uploads.fail(upload, err)
 		logErrorAndRespond(w, "Failed to save the file", err, http.StatusInternalServerError)
 		return
 	}
@@ -67,18 +118,32 @@ func (s Server) handleJsonUpload(w http.ResponseWriter, r *http.Request) {
 
 	// Upload the file to S3 and remove from tmp folder
 	uniqueFileName := fmt.Sprintf("%s-%s", uuid.New().String(), handler.Filename)
-	s3Location, err := s.UploadToS3(filePath, uniqueFileName)
+	s3Location, err := s.UploadToS3(userID, filePath, uniqueFileName, func(progress UploadProgress) {
+		s.Uploads.setProgress(upload, progress)
+	})
 	if err != nil {
 		log.Printf("Failed uploaded file to s3: %s", filePath)
+		s.Uploads.fail(upload, err)
 		logErrorAndRespond(w, "Failed to upload file", err, http.StatusInternalServerError)
 		return
 	}
 	tokenEstimate := estimateTokenCount(string(fileBytes))
 
 	InitialMessageToUser := fmt.Sprintf("Your JSON file %s uploaded successfully! How can I help you understand your file?", handler.Filename)
-	jChat, err := db.StartChat(s.DB, userID, handler.Filename, s3Location, InitialMessageToUser, tokenEstimate)
+	jChat, err := db.StartChat(s.DB, &db.JaiChat{
+		UserID:            userID,
+		JSON:              handler.Filename,
+		FileLocation:      s3Location,
+		FileTokenEstimate: tokenEstimate,
+		ExpiresAt:         s.retentionExpiry(time.Now()),
+		IngestMode:        ingestMode,
+		RecordPath:        recordPath,
+		LanguageModel:     model,
+		StageModels:       storedModels,
+	}, InitialMessageToUser)
 	if err != nil {
 		log.Printf("Failed to start chat: %v", err)
+		s.Uploads.fail(upload, err)
 		logErrorAndRespond(w, "Failed to start chat", err, http.StatusInternalServerError)
 		return
 	}
@@ -87,15 +152,35 @@ func (s Server) handleJsonUpload(w http.ResponseWriter, r *http.Request) {
 		err = db.InsertJSONCache(s.DB, jChat.UUID.ID, string(fileBytes))
 		if err != nil {
 			log.Printf("Failed to insert JSON cache: %v", err)
+			s.Uploads.fail(upload, err)
 			logErrorAndRespond(w, "Failed to insert JSON cache", err, http.StatusInternalServerError)
 			return
 		}
+	} else {
+		// Large files are queried through DuckDB, build the database once now instead of on every question
+		s.Uploads.processing(upload)
+		if err := s.prepareChatDuckDB(jChat, fileBytes); err != nil {
+			// The chat could not answer questions about the file, do not keep it
+			s.Uploads.fail(upload, err)
+			if err := s.discardChat(jChat); err != nil {
+				log.Printf("Failed to discard chat %s: %v", jChat.UUID.ID, err)
+			}
+			logErrorAndRespond(w, "Failed to load the JSON into DuckDB", err, http.StatusInternalServerError)
+			return
+		}
 	}
 
+	s.Uploads.complete(upload, jChat.UUID.ID)
+
 	chatProto := &proto.Chat{
-		ChatID:   jChat.UUID.ID,
-		UserID:   jChat.UserID,
-		JsonName: jChat.JSON,
+		ChatID:    jChat.UUID.ID,
+		UserID:    jChat.UserID,
+		JsonName:  jChat.JSON,
+		ExpiresAt: formatExpiresAt(jChat.ExpiresAt),
+		Model:     jChat.LanguageModel,
+		Models:    parseStageModels(jChat.StageModels).toProto(),
+
+		IngestionReport: parseIngestionReport(jChat.IngestionReport).toProto(),
 		Messages: []*proto.Message{{
 			Role:      openai.ChatMessageRoleAssistant,
 			Message:   InitialMessageToUser,
@@ -138,6 +223,20 @@ func validateJSONFormat(data []byte) error {
 	return nil
 }
 
+// validateRecordPath checks that the JSON Pointer chosen by the user points to an array or object in the file.
+func validateRecordPath(data []byte, recordPath string) error {
+	if recordPath == "" {
+		return nil
+	}
+
+	var jsonData interface{}
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return fmt.Errorf("invalid JSON file: %v", err)
+	}
+	_, err := extractRecords(jsonData, recordPath)
+	return err
+}
+
 func validateJSONFile(fileName string) error {
 	if filepath.Ext(fileName) != ".json" {
 		return errors.New("file is not a JSON file")
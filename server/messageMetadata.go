@@ -0,0 +1,85 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// messageMetadata records how an assistant message was produced, so answers can be verified later. The final
+// query is stored in ChatMessages.SQL.
+type messageMetadata struct {
+	FailedAttempts []failedQuery   `json:"failedAttempts,omitempty"` // Generated queries that were rejected or failed, in order
+	Result         *queryResult    `json:"result,omitempty"`         // Rows of the final query as given to the model
+	Timings        *messageTimings `json:"timings"`
+}
+
+type failedQuery struct {
+	SQL   string `json:"sql"`
+	Error string `json:"error"`
+}
+
+// messageTimings are the milliseconds spent in each stage, stages that did not run are zero.
+type messageTimings struct {
+	ValidationMs    int64 `json:"validationMs"`
+	SQLGenerationMs int64 `json:"sqlGenerationMs"` // Model calls writing and fixing queries
+	QueryMs         int64 `json:"queryMs"`         // Running the generated queries
+	AnswerMs        int64 `json:"answerMs"`
+	TotalMs         int64 `json:"totalMs"`
+}
+
+func newMessageMetadata() *messageMetadata {
+	return &messageMetadata{Timings: &messageTimings{}}
+}
+
+// elapsedMs returns the milliseconds elapsed since start.
+func elapsedMs(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
+
+// marshal encodes the metadata for ChatMessages.Metadata. The result rows are converted to JSON values first, so
+// they are read back the same way.
+func (m *messageMetadata) marshal(start time.Time) string {
+	m.Timings.TotalMs = elapsedMs(start)
+
+	stored := *m
+	stored.Result = m.Result.jsonRows()
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		log.Printf("Failed to marshal message metadata: %v", err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// addToProto sets the fields of the message stored in the metadata.
+func (m *messageMetadata) addToProto(message *proto.Message) {
+	message.ResultTable = m.Result.toProto()
+	for _, attempt := range m.FailedAttempts {
+		message.FailedAttempts = append(message.FailedAttempts, &proto.FailedQuery{Sql: attempt.SQL, Error: attempt.Error})
+	}
+	if m.Timings != nil {
+		message.Timings = &proto.MessageTimings{
+			ValidationMs:    m.Timings.ValidationMs,
+			SqlGenerationMs: m.Timings.SQLGenerationMs,
+			QueryMs:         m.Timings.QueryMs,
+			AnswerMs:        m.Timings.AnswerMs,
+			TotalMs:         m.Timings.TotalMs,
+		}
+	}
+}
+
+// parseMessageMetadata reads the metadata stored with a message. Messages without metadata return nil.
+func parseMessageMetadata(stored string) *messageMetadata {
+	if stored == "" {
+		return nil
+	}
+
+	var metadata messageMetadata
+	if err := json.Unmarshal([]byte(stored), &metadata); err != nil {
+		log.Printf("Failed to parse message metadata: %v", err)
+		return nil
+	}
+	return &metadata
+}
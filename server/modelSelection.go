@@ -0,0 +1,151 @@
+package server
+
+import (
+	"JsonAI/db"
+	"JsonAI/proto"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// stageModels names the model used by each stage of answering a question. Stages that did not run are empty.
+type stageModels struct {
+	Validation string `json:"validation,omitempty"` // Checks whether the question can be answered from the data
+	SQL        string `json:"sql,omitempty"`        // Generates and fixes the queries
+	Answer     string `json:"answer,omitempty"`     // Answers the question from the query results or the JSON
+}
+
+// parseAllowedModels reads the comma separated models chats and requests may choose. The configured models of
+// every stage are always allowed.
+func parseAllowedModels(list string, configured stageModels) map[string]bool {
+	allowed := map[string]bool{
+		configured.Validation: true,
+		configured.SQL:        true,
+		configured.Answer:     true,
+	}
+	for _, model := range strings.Split(list, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			allowed[model] = true
+		}
+	}
+	return allowed
+}
+
+// allStages returns the model for every stage, an empty model overrides none.
+func allStages(model string) stageModels {
+	return stageModels{Validation: model, SQL: model, Answer: model}
+}
+
+// override returns the models with every stage set in overrides replaced.
+func (m stageModels) override(overrides stageModels) stageModels {
+	if overrides.Validation != "" {
+		m.Validation = overrides.Validation
+	}
+	if overrides.SQL != "" {
+		m.SQL = overrides.SQL
+	}
+	if overrides.Answer != "" {
+		m.Answer = overrides.Answer
+	}
+	return m
+}
+
+// validateStageModels checks every model chosen by a client for a stage.
+func (s Server) validateStageModels(models stageModels) error {
+	for _, model := range []string{models.Validation, models.SQL, models.Answer} {
+		if err := s.validateModel(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateModel checks that a model chosen by a client is in the allow-list, an empty model keeps the default.
+func (s Server) validateModel(model string) error {
+	if model == "" || s.AllowedModels[model] {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(s.AllowedModels))
+	for name := range s.AllowedModels {
+		allowed = append(allowed, name)
+	}
+	sort.Strings(allowed)
+	return fmt.Errorf("model %q is not allowed, choose one of %s", model, strings.Join(allowed, ", "))
+}
+
+// selectModels returns the models answering a question: the configured model of each stage, replaced by the
+// stages chosen for the chat and then by the stages set in the request overrides. Later overrides take precedence.
+// The request overrides are checked against the allow-list; the chat models were checked when they were chosen, so
+// stages whose model was removed from the allow-list since then use the configured model instead.
+func (s Server) selectModels(chat stageModels, overrides ...stageModels) (stageModels, error) {
+	models := s.Models.override(s.stillAllowed(chat))
+	for _, override := range overrides {
+		if err := s.validateStageModels(override); err != nil {
+			return stageModels{}, err
+		}
+		models = models.override(override)
+	}
+	return models, nil
+}
+
+// stillAllowed returns the models with every stage whose model is no longer in the allow-list cleared.
+func (s Server) stillAllowed(models stageModels) stageModels {
+	for _, stage := range []*string{&models.Validation, &models.SQL, &models.Answer} {
+		if err := s.validateModel(*stage); err != nil {
+			log.Printf("Using the configured model instead of the chat model: %v", err)
+			*stage = ""
+		}
+	}
+	return models
+}
+
+// marshal encodes the models for ChatMessages.Models.
+func (m stageModels) marshal() string {
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("Failed to marshal models: %v", err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// parseStageModels reads the models stored with a message or chat. Without models it returns nil.
+func parseStageModels(stored string) *stageModels {
+	if stored == "" {
+		return nil
+	}
+
+	var models stageModels
+	if err := json.Unmarshal([]byte(stored), &models); err != nil {
+		log.Printf("Failed to parse models: %v", err)
+		return nil
+	}
+	return &models
+}
+
+func (m *stageModels) toProto() *proto.StageModels {
+	if m == nil {
+		return nil
+	}
+	return &proto.StageModels{Validation: m.Validation, Sql: m.SQL, Answer: m.Answer}
+}
+
+func stageModelsFromProto(models *proto.StageModels) stageModels {
+	if models == nil {
+		return stageModels{}
+	}
+	return stageModels{Validation: models.Validation, SQL: models.Sql, Answer: models.Answer}
+}
+
+// chatStageModels returns the models chosen for the chat: the model of every stage, replaced by the models chosen
+// for single stages.
+func chatStageModels(jChat *db.JaiChat) stageModels {
+	models := allStages(jChat.LanguageModel)
+	if stages := parseStageModels(jChat.StageModels); stages != nil {
+		models = models.override(*stages)
+	}
+	return models
+}
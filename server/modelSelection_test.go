@@ -0,0 +1,63 @@
+package server
+
+import (
+	"JsonAI/db"
+	"testing"
+)
+
+func TestSelectModelsOverridesSingleStages(t *testing.T) {
+	configured := stageModels{Validation: "small", SQL: "coder", Answer: "writer"}
+	s := Server{Models: configured, AllowedModels: parseAllowedModels("large,other", configured)}
+
+	tests := []struct {
+		name      string
+		chat      *db.JaiChat
+		request   stageModels
+		requested string
+		want      stageModels
+	}{
+		{"configured", &db.JaiChat{}, stageModels{}, "", configured},
+		{"one stage of the request", &db.JaiChat{}, stageModels{SQL: "large"}, "", stageModels{Validation: "small", SQL: "large", Answer: "writer"}},
+		{"one stage of the chat", &db.JaiChat{StageModels: `{"answer":"large"}`}, stageModels{}, "", stageModels{Validation: "small", SQL: "coder", Answer: "large"}},
+		{"chat and request stages", &db.JaiChat{StageModels: `{"answer":"large"}`}, stageModels{SQL: "other"}, "", stageModels{Validation: "small", SQL: "other", Answer: "large"}},
+		{"request stage over chat stage", &db.JaiChat{StageModels: `{"sql":"large"}`}, stageModels{SQL: "other"}, "", stageModels{Validation: "small", SQL: "other", Answer: "writer"}},
+		{"chat stage over chat model", &db.JaiChat{LanguageModel: "large", StageModels: `{"validation":"small"}`}, stageModels{}, "", stageModels{Validation: "small", SQL: "large", Answer: "large"}},
+		{"request model over chat", &db.JaiChat{LanguageModel: "large", StageModels: `{"sql":"coder"}`}, stageModels{}, "other", allStages("other")},
+		{"request stage over request model", &db.JaiChat{}, stageModels{Answer: "large"}, "other", stageModels{Validation: "other", SQL: "other", Answer: "large"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := s.selectModels(chatStageModels(test.chat), allStages(test.requested), test.request)
+			if err != nil {
+				t.Fatalf("Failed to select models: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSelectModelsRejectsModelsNotAllowed(t *testing.T) {
+	configured := allStages("small")
+	s := Server{Models: configured, AllowedModels: parseAllowedModels("large", configured)}
+
+	if _, err := s.selectModels(stageModels{}, stageModels{SQL: "unknown"}); err == nil {
+		t.Errorf("A stage model that is not allowed was accepted")
+	}
+}
+
+func TestSelectModelsFallsBackForChatModelsNoLongerAllowed(t *testing.T) {
+	configured := stageModels{Validation: "small", SQL: "coder", Answer: "writer"}
+	s := Server{Models: configured, AllowedModels: parseAllowedModels("large", configured)}
+
+	chat := &db.JaiChat{LanguageModel: "removed", StageModels: `{"answer":"large"}`}
+	got, err := s.selectModels(chatStageModels(chat))
+	if err != nil {
+		t.Fatalf("Failed to select models: %v", err)
+	}
+	if want := (stageModels{Validation: "small", SQL: "coder", Answer: "large"}); got != want {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
@@ -2,25 +2,76 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/sashabaranov/go-openai"
+	"io"
+	"strings"
 )
 
 const (
-	model = "gpt-4o"
+	defaultModel = "gpt-4o"
 )
 
-// OpenAIChat Function to chat with OpenAI
-func (s Server) OpenAIChat(messages *[]openai.ChatCompletionMessage) (string, error) {
-	client := openai.NewClient(s.OpenApiKey)
-	response, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+// openAIModel talks to the OpenAI API or any server implementing its chat completions endpoint, such as vLLM or
+// Ollama.
+type openAIModel struct {
+	client *openai.Client
+}
+
+// newOpenAIModel creates the client once, it is safe for concurrent use. An empty baseURL uses the OpenAI API.
+func newOpenAIModel(apiKey, baseURL string) *openAIModel {
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	return &openAIModel{client: openai.NewClientWithConfig(config)}
+}
+
+func (m *openAIModel) Chat(ctx context.Context, model string, messages []openai.ChatCompletionMessage) (string, error) {
+	response, err := m.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
 		Model:    model,
-		Messages: *messages,
+		Messages: messages,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get response: %v", err)
 	}
-	*messages = append(*messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: response.Choices[0].Message.Content})
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("failed to get response: no choices returned")
+	}
 
 	return response.Choices[0].Message.Content, nil
 }
+
+func (m *openAIModel) ChatStream(ctx context.Context, model string, messages []openai.ChatCompletionMessage, onToken func(token string) error) (string, error) {
+	stream, err := m.client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get response: %v", err)
+	}
+	defer stream.Close()
+
+	var response strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get response: %v", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		token := chunk.Choices[0].Delta.Content
+		response.WriteString(token)
+		if err := onToken(token); err != nil {
+			return "", err
+		}
+	}
+
+	return response.String(), nil
+}
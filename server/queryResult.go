@@ -0,0 +1,239 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marcboeker/go-duckdb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Rows counted beyond the row limit of a query, the count of omitted rows stops there
+	omittedRowsProbe = 1000
+
+	truncationMarker = "…"
+)
+
+// queryLimits caps how much of a query result is collected, results are only meant to be read by the model.
+type queryLimits struct {
+	MaxRows  int
+	MaxBytes int // Size of the rows as rendered for the model
+}
+
+// queryResult holds the collected rows of a query in the column order of the query.
+type queryResult struct {
+	Columns     []string        `json:"columns"`
+	Rows        [][]interface{} `json:"rows"`
+	OmittedRows int64           `json:"omittedRows,omitempty"` // Rows returned by the query that were not collected because of the limits
+
+	OmittedRowsCapped bool `json:"omittedRowsCapped,omitempty"` // Counting stopped at omittedRowsProbe, even more rows were omitted
+	TruncatedCells    bool `json:"truncatedCells,omitempty"`    // Values too long for the size limit were cut, ending in truncationMarker
+}
+
+// markdown renders the result as a Markdown table for the model, followed by a marker when rows were omitted.
+func (r *queryResult) markdown() string {
+	var result strings.Builder
+	result.WriteString(markdownRow(r.Columns))
+	separators := make([]string, len(r.Columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	result.WriteString(markdownRow(separators))
+
+	for _, row := range r.Rows {
+		result.WriteString(markdownRow(formatResultRow(row)))
+	}
+
+	if r.TruncatedCells {
+		result.WriteString(fmt.Sprintf("(values ending in %s were truncated)\n", truncationMarker))
+	}
+	if r.OmittedRowsCapped {
+		result.WriteString(fmt.Sprintf("(at least %d more rows omitted)\n", r.OmittedRows))
+	} else if r.OmittedRows > 0 {
+		result.WriteString(fmt.Sprintf("(%d more rows omitted)\n", r.OmittedRows))
+	}
+	return result.String()
+}
+
+// truncateResultRow replaces the values of the row rendered longer than maxBytes with their rendering cut to
+// maxBytes and ending in truncationMarker. It reports whether any value was cut.
+func truncateResultRow(row []interface{}, maxBytes int) bool {
+	// Room for the cell separator and the marker
+	maxBytes -= len(" | ") + len(truncationMarker)
+
+	truncated := false
+	for i, value := range row {
+		cell := formatResultValue(value)
+		if len(cell) <= maxBytes {
+			continue
+		}
+
+		cut := 0
+		for index := range cell {
+			if index > maxBytes {
+				break
+			}
+			cut = index
+		}
+		row[i] = cell[:cut] + truncationMarker
+		truncated = true
+	}
+	return truncated
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+func formatResultRow(row []interface{}) []string {
+	cells := make([]string, len(row))
+	for i, value := range row {
+		cells[i] = formatResultValue(value)
+	}
+	return cells
+}
+
+// formatResultValue renders a value scanned from DuckDB the same way regardless of its Go type: NULL for nulls,
+// exact decimals, dates without a time and nested values as JSON.
+func formatResultValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return formatResultTime(v)
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return `\x` + hex.EncodeToString(v)
+	case duckdb.Interval:
+		return fmt.Sprintf("%d months %d days %s", v.Months, v.Days, time.Duration(v.Micros)*time.Microsecond)
+	case *big.Int:
+		return v.String()
+	case map[string]interface{}, []interface{}, duckdb.Map:
+		serialized, err := json.Marshal(jsonResultValue(v))
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(serialized)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatResultTime(t time.Time) string {
+	t = t.UTC()
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05.999999")
+}
+
+// jsonResultValue converts a value scanned from DuckDB into one that encoding/json and structpb can represent.
+func jsonResultValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		// Integers JSON numbers cannot hold exactly are kept as strings
+		number, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if math.Abs(number) >= 1<<53 {
+			return fmt.Sprint(v)
+		}
+		return number
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = jsonResultValue(item)
+		}
+		return converted
+	case duckdb.Map:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[formatResultValue(key)] = jsonResultValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = jsonResultValue(item)
+		}
+		return converted
+	default:
+		return formatResultValue(v)
+	}
+}
+
+// jsonRows returns a copy of the result with every value converted by jsonResultValue, for storing it as JSON.
+func (r *queryResult) jsonRows() *queryResult {
+	if r == nil {
+		return nil
+	}
+
+	converted := &queryResult{Columns: r.Columns, OmittedRows: r.OmittedRows, OmittedRowsCapped: r.OmittedRowsCapped, TruncatedCells: r.TruncatedCells}
+	for _, row := range r.Rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = jsonResultValue(value)
+		}
+		converted.Rows = append(converted.Rows, values)
+	}
+	return converted
+}
+
+func (r *queryResult) toProto() *proto.ResultTable {
+	if r == nil {
+		return nil
+	}
+
+	table := &proto.ResultTable{
+		Columns:           r.Columns,
+		OmittedRows:       r.OmittedRows,
+		OmittedRowsCapped: r.OmittedRowsCapped,
+		TruncatedCells:    r.TruncatedCells,
+	}
+	for _, row := range r.Rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = jsonResultValue(value)
+		}
+		list, err := structpb.NewList(values)
+		if err != nil {
+			// Only happens for values jsonResultValue does not convert
+			list = &structpb.ListValue{}
+			for _, cell := range formatResultRow(row) {
+				list.Values = append(list.Values, structpb.NewStringValue(cell))
+			}
+		}
+		table.Rows = append(table.Rows, list)
+	}
+	return table
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"JsonAI/db"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	datasetExpiredResponse = "The data for this chat has expired and was deleted. The chat history is still available, please upload the file again to ask new questions."
+)
+
+// retentionExpiry returns when data uploaded now should be deleted, or nil if it is kept forever.
+func (s Server) retentionExpiry(now time.Time) *time.Time {
+	if s.RetentionDays <= 0 {
+		return nil
+	}
+
+	expiresAt := now.AddDate(0, 0, s.RetentionDays)
+	return &expiresAt
+}
+
+// startRetentionSweeper periodically deletes the uploaded data of expired chats.
+func (s Server) startRetentionSweeper() {
+	if s.RetentionSweepInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.RetentionSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			s.sweepExpiredChats()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s Server) sweepExpiredChats() {
+	chats, err := db.GetChatsToExpire(s.DB, time.Now())
+	if err != nil {
+		log.Printf("Failed to retrieve expired chats: %v", err)
+		return
+	}
+
+	for _, chat := range chats {
+		if err := s.expireChat(chat); err != nil {
+			// Leave the chat unmarked so the next sweep retries it
+			log.Printf("Failed to expire chat %s: %v", chat.UUID.ID, err)
+			continue
+		}
+		log.Printf("Expired data for chat %s", chat.UUID.ID)
+	}
+}
+
+func (s Server) expireChat(chat *db.JaiChat) error {
+	if err := s.deleteChatData(chat); err != nil {
+		return err
+	}
+	return db.MarkChatExpired(s.DB, chat.UUID.ID)
+}
+
+// discardChat removes a chat whose upload could not be completed, together with its uploaded data.
+func (s Server) discardChat(chat *db.JaiChat) error {
+	if err := s.deleteChatData(chat); err != nil {
+		return err
+	}
+	return db.DeleteChat(s.DB, chat.UUID.ID)
+}
+
+// deleteChatData deletes the uploaded file and everything built from it.
+func (s Server) deleteChatData(chat *db.JaiChat) error {
+	bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(chat.FileLocation)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.DeleteFromS3(ctx, bucket, key); err != nil {
+		return err
+	}
+
+	if chat.DuckDBLocation != "" {
+		bucket, key, err := s.AWS.getBucketAndKeyFromS3URL(chat.DuckDBLocation)
+		if err != nil {
+			return err
+		}
+		if err := s.DeleteFromS3(ctx, bucket, key); err != nil {
+			return err
+		}
+	}
+	s.DuckDBCache.remove(chat.UUID.ID)
+
+	return db.DeleteJSONCache(s.DB, chat.UUID.ID)
+}
+
+func formatExpiresAt(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return ""
+	}
+	return expiresAt.Format(time.RFC3339)
+}
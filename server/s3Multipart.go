@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	minPartSize    = 5 << 20 // S3 rejects smaller parts except for the last one
+	maxPartRetries = 3
+)
+
+// UploadProgress is reported after every part that finishes uploading.
+type UploadProgress struct {
+	BytesUploaded  int64
+	TotalBytes     int64
+	PartsCompleted int
+	TotalParts     int
+}
+
+type s3Object struct {
+	key         string
+	body        io.ReaderAt
+	size        int64
+	contentType string
+	metadata    map[string]string
+}
+
+// putS3Object uploads the object with a single PutObject when it fits in one part, otherwise with a parallel
+// multipart upload.
+func (s Server) putS3Object(ctx context.Context, object s3Object, progress func(UploadProgress)) error {
+	partSize := s.AWS.PartSize
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+
+	if object.size <= partSize {
+		return s.putSinglePart(ctx, object, progress)
+	}
+	return s.putMultipart(ctx, object, partSize, progress)
+}
+
+func (s Server) putSinglePart(ctx context.Context, object s3Object, progress func(UploadProgress)) error {
+	err := retryPart(ctx, s.AWS.PartTimeout, func(partCtx context.Context) error {
+		_, err := s.S3.PutObject(partCtx, &s3.PutObjectInput{
+			Bucket:        aws.String(s.AWS.BucketName),
+			Key:           aws.String(object.key),
+			Body:          io.NewSectionReader(object.body, 0, object.size),
+			ContentLength: aws.Int64(object.size),
+			ContentType:   aws.String(object.contentType),
+			Metadata:      object.metadata,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if progress != nil {
+		progress(UploadProgress{BytesUploaded: object.size, TotalBytes: object.size, PartsCompleted: 1, TotalParts: 1})
+	}
+	return nil
+}
+
+func (s Server) putMultipart(ctx context.Context, object s3Object, partSize int64, progress func(UploadProgress)) error {
+	created, err := s.S3.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.AWS.BucketName),
+		Key:         aws.String(object.key),
+		ContentType: aws.String(object.contentType),
+		Metadata:    object.metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start multipart upload: %v", err)
+	}
+	uploadID := created.UploadId
+
+	totalParts := int((object.size + partSize - 1) / partSize)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		parts     = make([]types.CompletedPart, 0, totalParts)
+		firstErr  error
+		uploaded  int64
+		semaphore = make(chan struct{}, max(s.AWS.UploadConcurrency, 1))
+	)
+
+	for i := 0; i < totalParts; i++ {
+		partNumber := int32(i + 1)
+		offset := int64(i) * partSize
+		size := min(partSize, object.size-offset)
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			var etag *string
+			err := retryPart(ctx, s.AWS.PartTimeout, func(partCtx context.Context) error {
+				result, err := s.S3.UploadPart(partCtx, &s3.UploadPartInput{
+					Bucket:        aws.String(s.AWS.BucketName),
+					Key:           aws.String(object.key),
+					UploadId:      uploadID,
+					PartNumber:    aws.Int32(partNumber),
+					Body:          io.NewSectionReader(object.body, offset, size),
+					ContentLength: aws.Int64(size),
+				})
+				if err != nil {
+					return err
+				}
+				etag = result.ETag
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to upload part %d: %v", partNumber, err)
+					cancel()
+				}
+				return
+			}
+
+			parts = append(parts, types.CompletedPart{ETag: etag, PartNumber: aws.Int32(partNumber)})
+			uploaded += size
+			if progress != nil {
+				progress(UploadProgress{BytesUploaded: uploaded, TotalBytes: object.size, PartsCompleted: len(parts), TotalParts: totalParts})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr == nil {
+		sort.Slice(parts, func(i, j int) bool { return *parts[i].PartNumber < *parts[j].PartNumber })
+		_, firstErr = s.S3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(s.AWS.BucketName),
+			Key:             aws.String(object.key),
+			UploadId:        uploadID,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+
+	if firstErr != nil {
+		s.abortMultipart(object.key, uploadID)
+		return firstErr
+	}
+	return nil
+}
+
+// abortMultipart removes the parts of a failed upload so they do not linger in the bucket.
+func (s Server) abortMultipart(key string, uploadID *string) {
+	// The upload context may already be cancelled, the cleanup gets its own
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := s.S3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.AWS.BucketName),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+	if err != nil {
+		log.Printf("Failed to abort multipart upload of %q: %v", key, err)
+	}
+}
+
+// retryPart runs fn with a per-attempt timeout, retrying with a short backoff.
+func retryPart(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= maxPartRetries; attempt++ {
+		partCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = fn(partCtx)
+		cancel()
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		log.Printf("Upload attempt %d of %d failed: %v", attempt, maxPartRetries, err)
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"JsonAI/db"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+const (
+	// Bump when the content of the schema context changes, contexts stored with an older version are rebuilt on
+	// the next question.
+	schemaContextVersion = 1
+
+	sampleRowsLimit = 5
+	sampleRowsBytes = 4000
+)
+
+// SchemaContext is everything the prompts need to know about a chat's data. It is built once when the data is
+// loaded into DuckDB and stored with the chat, so questions do not have to inspect the database again.
+type SchemaContext struct {
+	Version          int    `json:"version"`
+	Schema           string `json:"schema"`  // Tables, nested fields, sample rows and column profiles
+	Preview          string `json:"preview"` // Start of the uploaded JSON
+	ColumnMapping    string `json:"columnMapping,omitempty"`
+	IngestionSummary string `json:"ingestionSummary,omitempty"`
+}
+
+// buildSchemaContext describes the tables of the JSON loaded into duckDB.
+func buildSchemaContext(ctx context.Context, duckDB *sql.DB, schema *tableSchema, report *IngestionReport, profile *DatasetProfile, jsonPreview string) (*SchemaContext, error) {
+	tables, err := describeTables(duckDB, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tables: %v", err)
+	}
+
+	uniqueFields, err := extractUniqueFieldsFromJSONColumns(duckDB, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract JSON fields: %v", err)
+	}
+
+	sampleRows, err := queryDuckDB(ctx, duckDB, fmt.Sprintf("SELECT * FROM %s LIMIT %d;", quoteIdentifier(schema.Name), sampleRowsLimit), queryLimits{MaxRows: sampleRowsLimit, MaxBytes: sampleRowsBytes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sample rows: %v", err)
+	}
+
+	totalSchema := "Table Schema:\n" + tables
+	if len(uniqueFields) > 0 {
+		totalSchema = totalSchema + "\n\nExpanded Fields from JSON Columns:\n" + uniqueFields
+	}
+
+	totalSchema = totalSchema + fmt.Sprintf("\n\nSample Rows of %s:\n", schema.Name) + sampleRows.markdown()
+
+	if profileText := profile.describe(); profileText != "" {
+		totalSchema = totalSchema + "\n\nColumn Profiles:\n" + profileText
+	}
+
+	// Let the model know when the tables do not hold every record of the file
+	ingestionSummary := report.summary()
+	if ingestionSummary != "" {
+		totalSchema = totalSchema + "\n\nIncomplete Data:\n" + ingestionSummary
+	}
+
+	return &SchemaContext{
+		Version:          schemaContextVersion,
+		Schema:           totalSchema,
+		Preview:          jsonPreview,
+		ColumnMapping:    schema.describeColumnMapping(),
+		IngestionSummary: ingestionSummary,
+	}, nil
+}
+
+// parseSchemaContext reads the context stored with a chat. It returns nil for chats without a context or with
+// one of an older version.
+func parseSchemaContext(stored string) *SchemaContext {
+	if stored == "" {
+		return nil
+	}
+
+	var schemaContext SchemaContext
+	if err := json.Unmarshal([]byte(stored), &schemaContext); err != nil {
+		log.Printf("Failed to parse schema context: %v", err)
+		return nil
+	}
+	if schemaContext.Version != schemaContextVersion {
+		return nil
+	}
+	return &schemaContext
+}
+
+// chatSchemaContext returns the stored schema context of the chat, building and storing it from the chat's
+// database when it is missing or outdated.
+func (s Server) chatSchemaContext(ctx context.Context, jChat *db.JaiChat, chatDB *chatDuckDB) (*SchemaContext, error) {
+	if schemaContext := parseSchemaContext(jChat.SchemaContext); schemaContext != nil {
+		return schemaContext, nil
+	}
+
+	report := parseIngestionReport(jChat.IngestionReport)
+	profile := parseDatasetProfile(jChat.DatasetProfile)
+	schemaContext, err := buildSchemaContext(ctx, chatDB.DB, chatDB.Schema, report, profile, chatDB.Preview)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaContextJSON, err := json.Marshal(schemaContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema context: %v", err)
+	}
+
+	// The context can be rebuilt on the next question, answering this one does not depend on saving it
+	if err := db.SetChatSchemaContext(s.DB, jChat.UUID.ID, string(schemaContextJSON)); err != nil {
+		log.Printf("Failed to save schema context of chat %s: %v", jChat.UUID.ID, err)
+	}
+	jChat.SchemaContext = string(schemaContextJSON)
+	return schemaContext, nil
+}
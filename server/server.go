@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gorilla/mux"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lpernett/godotenv"
@@ -17,23 +18,48 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 )
 
 type Server struct {
-	HTTPPort   string
-	GRPCPort   string
-	DB         *gorm.DB
-	AWS        AwsConfig
-	OpenApiKey string
+	HTTPPort string
+	GRPCPort string
+	DB       *gorm.DB
+	AWS      AwsConfig
+	S3       *s3.Client
+	Keys     *Keyring
+	Uploads  *uploadTracker
+	LLM      ChatModel
+
+	Models        stageModels     // Configured model of every stage of answering a question
+	AllowedModels map[string]bool // Models chats and requests may choose instead
+
+	RetentionDays          int // Retention period for uploaded data, 0 keeps data forever
+	RetentionSweepInterval time.Duration
+	IngestMode             string // Default ingestion mode of uploaded JSON, see ingestModeNested and ingestModeShred
+	DuckDBCache            *duckDBCache
+	DuckDBMemoryLimit      string // Memory limit of the DuckDB running generated queries, e.g. 512MB
+	DuckDBThreads          int
+	QueryTimeout           time.Duration // Generated queries running longer are cancelled
+	QueryLimits            queryLimits
+	HistoryMaxTokens       int // Earlier messages of a chat given to the prompts, older ones are summarized. 0 uses the default
 	proto.UnimplementedJsonAIServiceServer
 }
 
 type AwsConfig struct {
-	AccessKey  string
-	SecretKey  string
-	Region     string
-	BucketName string
+	AccessKey    string
+	SecretKey    string
+	Region       string
+	BucketName   string
+	Endpoint     string // Optional S3-compatible endpoint, e.g. http://localhost:9000 for MinIO
+	UsePathStyle bool
+	Presign      bool // Hand out presigned URLs for downloads instead of streaming through the server
+	PresignTTL   time.Duration
+
+	PartSize          int64 // Files larger than this are uploaded in parts of this size
+	UploadConcurrency int
+	PartTimeout       time.Duration
 }
 
 func getEnv(key, fallback string) string {
@@ -53,10 +79,112 @@ func NewServer() *Server {
 	openAIKey := getEnv("JAI_OPENAI_KEY", "")
 
 	awsConfig := AwsConfig{
-		AccessKey:  getEnv("JAI_AWS_ACCESS_KEY", ""),
-		SecretKey:  getEnv("JAI_AWS_SECRET_KEY", ""),
-		Region:     getEnv("JAI_AWS_REGION", "us-east-1"),
-		BucketName: getEnv("JAI_AWS_BUCKET", ""),
+		AccessKey:    getEnv("JAI_AWS_ACCESS_KEY", ""),
+		SecretKey:    getEnv("JAI_AWS_SECRET_KEY", ""),
+		Region:       getEnv("JAI_AWS_REGION", "us-east-1"),
+		BucketName:   getEnv("JAI_AWS_BUCKET", ""),
+		Endpoint:     getEnv("JAI_AWS_ENDPOINT", ""),
+		UsePathStyle: getEnv("JAI_AWS_USE_PATH_STYLE", "false") == "true",
+		Presign:      getEnv("JAI_AWS_PRESIGN", "true") == "true",
+	}
+
+	presignTTL, err := time.ParseDuration(getEnv("JAI_AWS_PRESIGN_TTL", "15m"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_AWS_PRESIGN_TTL: %v", err)
+	}
+	awsConfig.PresignTTL = presignTTL
+
+	partSizeMB, err := strconv.Atoi(getEnv("JAI_AWS_PART_SIZE_MB", "8"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_AWS_PART_SIZE_MB: %v", err)
+	}
+	awsConfig.PartSize = int64(partSizeMB) << 20
+
+	awsConfig.UploadConcurrency, err = strconv.Atoi(getEnv("JAI_AWS_UPLOAD_CONCURRENCY", "4"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_AWS_UPLOAD_CONCURRENCY: %v", err)
+	}
+
+	awsConfig.PartTimeout, err = time.ParseDuration(getEnv("JAI_AWS_PART_TIMEOUT", "2m"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_AWS_PART_TIMEOUT: %v", err)
+	}
+
+	s3Client, err := newS3Client(awsConfig)
+	if err != nil {
+		log.Fatalf("Failed to create S3 client: %v", err)
+	}
+
+	keyring, err := newKeyring(getEnv("JAI_MASTER_KEY", ""), getEnv("JAI_PREVIOUS_MASTER_KEYS", ""))
+	if err != nil {
+		log.Fatalf("Failed to load master keys: %v", err)
+	}
+
+	retentionDays, err := strconv.Atoi(getEnv("JAI_RETENTION_DAYS", "0"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_RETENTION_DAYS: %v", err)
+	}
+	sweepInterval, err := time.ParseDuration(getEnv("JAI_RETENTION_SWEEP_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_RETENTION_SWEEP_INTERVAL: %v", err)
+	}
+
+	ingestMode := getEnv("JAI_INGEST_MODE", ingestModeNested)
+	if !isValidIngestMode(ingestMode) {
+		log.Fatalf("Invalid JAI_INGEST_MODE: %s", ingestMode)
+	}
+
+	cacheMaxMB, err := strconv.Atoi(getEnv("JAI_DUCKDB_CACHE_MAX_MB", "1024"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_DUCKDB_CACHE_MAX_MB: %v", err)
+	}
+	duckDBCache, err := newDuckDBCache(getEnv("JAI_DUCKDB_CACHE_DIR", "./tmp/duckdb"), int64(cacheMaxMB)<<20)
+	if err != nil {
+		log.Fatalf("Failed to create DuckDB cache: %v", err)
+	}
+
+	queryTimeout, err := time.ParseDuration(getEnv("JAI_QUERY_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid JAI_QUERY_TIMEOUT: %v", err)
+	}
+
+	queryMaxRows, err := strconv.Atoi(getEnv("JAI_QUERY_MAX_ROWS", "200"))
+	if err != nil || queryMaxRows < 1 {
+		log.Fatalf("Invalid JAI_QUERY_MAX_ROWS: %s", getEnv("JAI_QUERY_MAX_ROWS", "200"))
+	}
+
+	queryMaxBytes, err := strconv.Atoi(getEnv("JAI_QUERY_MAX_BYTES", "8192"))
+	if err != nil || queryMaxBytes < 1 {
+		log.Fatalf("Invalid JAI_QUERY_MAX_BYTES: %s", getEnv("JAI_QUERY_MAX_BYTES", "8192"))
+	}
+
+	duckDBThreads, err := strconv.Atoi(getEnv("JAI_DUCKDB_THREADS", "2"))
+	if err != nil || duckDBThreads < 1 {
+		log.Fatalf("Invalid JAI_DUCKDB_THREADS: %s", getEnv("JAI_DUCKDB_THREADS", "2"))
+	}
+
+	// 0 uses the default budget
+	historyMaxTokens, err := strconv.Atoi(getEnv("JAI_HISTORY_MAX_TOKENS", "1500"))
+	if err != nil || historyMaxTokens < 0 {
+		log.Fatalf("Invalid JAI_HISTORY_MAX_TOKENS: %s", getEnv("JAI_HISTORY_MAX_TOKENS", "1500"))
+	}
+
+	llm, err := newChatModel(
+		getEnv("JAI_LLM_PROVIDER", llmProviderOpenAI),
+		openAIKey,
+		getEnv("JAI_LLM_BASE_URL", ""),
+		getEnv("JAI_LLM_SCRIPT", ""),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create LLM client: %v", err)
+	}
+
+	// Every stage uses JAI_LLM_MODEL unless it is configured on its own
+	llmModel := getEnv("JAI_LLM_MODEL", defaultModel)
+	models := stageModels{
+		Validation: getEnv("JAI_MODEL_VALIDATION", llmModel),
+		SQL:        getEnv("JAI_MODEL_SQL", llmModel),
+		Answer:     getEnv("JAI_MODEL_ANSWER", llmModel),
 	}
 
 	log.Println("Connecting to DB...")
@@ -66,11 +194,27 @@ func NewServer() *Server {
 	}
 
 	return &Server{
-		HTTPPort:   httpPort,
-		GRPCPort:   grpcPort,
-		OpenApiKey: openAIKey,
-		DB:         dbConn,
-		AWS:        awsConfig,
+		HTTPPort: httpPort,
+		GRPCPort: grpcPort,
+		LLM:      llm,
+		DB:       dbConn,
+		AWS:      awsConfig,
+		S3:       s3Client,
+		Keys:     keyring,
+		Uploads:  newUploadTracker(),
+
+		Models:        models,
+		AllowedModels: parseAllowedModels(getEnv("JAI_ALLOWED_MODELS", ""), models),
+
+		RetentionDays:          retentionDays,
+		RetentionSweepInterval: sweepInterval,
+		IngestMode:             ingestMode,
+		DuckDBCache:            duckDBCache,
+		DuckDBMemoryLimit:      getEnv("JAI_DUCKDB_MEMORY_LIMIT", "512MB"),
+		DuckDBThreads:          duckDBThreads,
+		QueryTimeout:           queryTimeout,
+		QueryLimits:            queryLimits{MaxRows: queryMaxRows, MaxBytes: queryMaxBytes},
+		HistoryMaxTokens:       historyMaxTokens,
 	}
 }
 
@@ -127,6 +271,8 @@ func (s Server) setupHTTP() error {
 	// Create a new HTTP router
 	r := mux.NewRouter()
 	r.HandleFunc("/json-ai/user/{userID}/upload-json", s.handleJsonUpload).Methods("POST")
+	r.HandleFunc("/json-ai/user/{userID}/chat/{chatID}/file/content", s.handleChatFileDownload).Methods("GET")
+	r.HandleFunc("/json-ai/user/{userID}/chat/{chatID}/stream", s.handleAskJsonAIStream).Methods("POST")
 
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -173,6 +319,8 @@ func (s Server) Serve() error {
 		return err
 	}
 
+	s.startRetentionSweeper()
+
 	if err := s.setupHTTP(); err != nil {
 		return err
 	}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Table functions generated queries may call. Everything else, like read_csv or read_text, could read files of
+// the server.
+var allowedTableFunctions = map[string]bool{
+	"unnest":          true,
+	"range":           true,
+	"generate_series": true,
+}
+
+// Functions generated queries may never call, even though external access is disabled.
+var deniedFunctions = map[string]bool{
+	"getenv":          true,
+	"current_setting": true,
+}
+
+// serializedSQL is the output of DuckDB's json_serialize_sql.
+type serializedSQL struct {
+	Error        bool                     `json:"error"`
+	ErrorMessage string                   `json:"error_message"`
+	Statements   []map[string]interface{} `json:"statements"`
+}
+
+// validateSQL parses a generated query with DuckDB and only accepts a single read-only SELECT (or WITH ... SELECT)
+// over the given tables. The error describes why a query was rejected so the model can fix it.
+func validateSQL(duckDB *sql.DB, query string, tables map[string]bool) error {
+	var serialized string
+	err := duckDB.QueryRow("SELECT json_serialize_sql(?::VARCHAR);", query).Scan(&serialized)
+	if err != nil {
+		return fmt.Errorf("failed to parse the query: %v", err)
+	}
+
+	var parsed serializedSQL
+	if err := json.Unmarshal([]byte(serialized), &parsed); err != nil {
+		return fmt.Errorf("failed to parse the query: %v", err)
+	}
+	if parsed.Error {
+		if strings.Contains(parsed.ErrorMessage, "Only SELECT") {
+			return fmt.Errorf("only SELECT queries are allowed")
+		}
+		return fmt.Errorf("invalid query: %s", parsed.ErrorMessage)
+	}
+	if len(parsed.Statements) != 1 {
+		return fmt.Errorf("exactly one SELECT statement is allowed, got %d statements", len(parsed.Statements))
+	}
+
+	return validateSQLNode(parsed.Statements[0], tables, nil)
+}
+
+// cteNames returns the names of the CTEs defined by the WITH clause of a query node.
+func cteNames(node map[string]interface{}) []string {
+	cteMap, _ := node["cte_map"].(map[string]interface{})
+	entries, _ := cteMap["map"].([]interface{})
+
+	var names []string
+	for _, entry := range entries {
+		if cte, ok := entry.(map[string]interface{}); ok {
+			if name, ok := cte["key"].(string); ok {
+				names = append(names, strings.ToLower(name))
+			}
+		}
+	}
+	return names
+}
+
+// validateSQLNode walks the parsed query and rejects references to other tables, files and table functions. ctes
+// holds the CTEs in scope, they only match table references without a catalog or schema.
+func validateSQLNode(node interface{}, tables, ctes map[string]bool) error {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if names := cteNames(v); len(names) > 0 {
+			// The CTEs of a WITH clause are visible in its query and the CTEs themselves
+			scope := make(map[string]bool, len(ctes)+len(names))
+			for name := range ctes {
+				scope[name] = true
+			}
+			for _, name := range names {
+				scope[name] = true
+			}
+			ctes = scope
+		}
+
+		switch v["type"] {
+		case "BASE_TABLE":
+			catalog, _ := v["catalog_name"].(string)
+			schema, _ := v["schema_name"].(string)
+			name, _ := v["table_name"].(string)
+			isCTE := catalog == "" && schema == "" && ctes[strings.ToLower(name)]
+			isTable := (catalog == "" || catalog == chatCatalog) && (schema == "" || schema == "main") && tables[strings.ToLower(name)]
+			if !isCTE && !isTable {
+				return fmt.Errorf("the query reads %q, only the tables in the schema can be queried", name)
+			}
+		case "TABLE_FUNCTION":
+			function, _ := v["function"].(map[string]interface{})
+			name, _ := function["function_name"].(string)
+			if !allowedTableFunctions[strings.ToLower(name)] {
+				return fmt.Errorf("the table function %s is not allowed", name)
+			}
+		}
+		if v["class"] == "FUNCTION" {
+			name, _ := v["function_name"].(string)
+			if deniedFunctions[strings.ToLower(name)] {
+				return fmt.Errorf("the function %s is not allowed", name)
+			}
+		}
+
+		for _, child := range v {
+			if err := validateSQLNode(child, tables, ctes); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := validateSQLNode(child, tables, ctes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sandboxDuckDB disables access to files and the network, limits the resources queries may use and locks the
+// configuration so generated queries cannot change it.
+func sandboxDuckDB(duckDB *sql.DB, memoryLimit string, threads int) error {
+	settings := []string{
+		"SET enable_external_access = false;",
+		fmt.Sprintf("SET memory_limit = '%s';", strings.ReplaceAll(memoryLimit, "'", "''")),
+		fmt.Sprintf("SET threads = %d;", threads),
+		"SET lock_configuration = true;",
+	}
+	for _, setting := range settings {
+		if _, err := duckDB.Exec(setting); err != nil {
+			return fmt.Errorf("failed to configure DuckDB (%s): %v", setting, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestValidateSQL(t *testing.T) {
+	duckDB := openTestDuckDB(t)
+	tables := map[string]bool{"json_data": true, "json_data_items": true}
+
+	tests := []struct {
+		name    string
+		query   string
+		allowed bool
+	}{
+		{"select", "SELECT count(*) FROM json_data WHERE price > 10;", true},
+		{"qualified table", "SELECT * FROM chat_data.main.json_data", true},
+		{"join", "SELECT d.name, i.sku FROM json_data d JOIN json_data_items i ON i.parent_id = d.id", true},
+		{"cte", "WITH expensive AS (SELECT * FROM json_data WHERE price > 10) SELECT count(*) FROM expensive", true},
+		{"nested cte", "SELECT * FROM (WITH e AS (SELECT * FROM json_data) SELECT * FROM e) t", true},
+		{"cte referencing earlier cte", "WITH a AS (SELECT * FROM json_data), b AS (SELECT * FROM a) SELECT * FROM b", true},
+		{"unnest", "SELECT unnest(tags) FROM json_data", true},
+		{"range", "SELECT * FROM range(10)", true},
+
+		{"cte bypass with qualified name", "WITH json_preview AS (SELECT 1) SELECT * FROM chat_data.main.json_preview", false},
+		{"cte bypass with schema", "WITH json_preview AS (SELECT 1) SELECT * FROM main.json_preview", false},
+		{"cte out of scope", "SELECT * FROM (WITH x AS (SELECT 1) SELECT * FROM x) t, x", false},
+		{"internal table", "SELECT * FROM json_preview", false},
+		{"other catalog", "SELECT * FROM other.main.json_data", false},
+		{"read_text", "SELECT * FROM read_text('/etc/passwd')", false},
+		{"read_csv", "SELECT * FROM read_csv('secrets.csv')", false},
+		{"file path", "SELECT * FROM '/etc/passwd'", false},
+		{"copy", "COPY json_data TO '/tmp/out.csv'", false},
+		{"multiple statements", "SELECT 1; SELECT 2", false},
+		{"information_schema", "SELECT * FROM information_schema.tables", false},
+		{"duckdb_settings", "SELECT * FROM duckdb_settings()", false},
+		{"current_setting", "SELECT current_setting('memory_limit')", false},
+		{"getenv", "SELECT getenv('JAI_OPENAI_KEY')", false},
+		{"set", "SET threads = 8", false},
+		{"pragma", "PRAGMA table_info('json_preview')", false},
+		{"attach", "ATTACH '/tmp/other.duckdb' AS other", false},
+		{"install", "INSTALL httpfs", false},
+		{"query", "SELECT * FROM query('SELECT * FROM json_preview')", false},
+		{"query_table", "SELECT * FROM query_table('json_preview')", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateSQL(duckDB, test.query, tables)
+			if test.allowed && err != nil {
+				t.Errorf("validateSQL(%q) rejected the query: %v", test.query, err)
+			}
+			if !test.allowed && err == nil {
+				t.Errorf("validateSQL(%q) accepted the query", test.query)
+			}
+		})
+	}
+}
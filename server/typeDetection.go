@@ -0,0 +1,202 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/marcboeker/go-duckdb"
+)
+
+// Typed columns detected from the values: ISO-8601 strings become DATE or TIMESTAMP, strings holding decimal
+// numbers become DECIMAL and integer columns named like a time holding epoch milliseconds become TIMESTAMP.
+const (
+	typeDate      = "DATE"
+	typeTimestamp = "TIMESTAMP"
+	typeDecimal   = "DECIMAL"
+
+	// Formats the typed values were parsed from
+	formatISODate       = "ISO-8601 date strings (YYYY-MM-DD)"
+	formatISOTimestamp  = "ISO-8601 timestamp strings, converted to UTC"
+	formatEpochMillis   = "epoch milliseconds, converted to UTC"
+	formatNumericString = "numeric strings"
+
+	// DuckDB's largest DECIMAL
+	maxDecimalDigits = 38
+
+	// Integers are only read as epoch milliseconds between 2000-01-01 and 2100-01-01
+	minEpochMillis = 946684800000
+	maxEpochMillis = 4102444800000
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	isoDatePattern       = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	isoTimestampPattern  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}`)
+	numericStringPattern = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?$`)
+
+	timestampLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02 15:04",
+	}
+
+	// Words in a column name that suggest an integer holds a point in time
+	epochNameWords = map[string]bool{
+		"at": true, "time": true, "timestamp": true, "date": true, "datetime": true, "epoch": true, "ts": true,
+		"ms": true, "millis": true, "created": true, "updated": true, "modified": true, "expires": true,
+	}
+)
+
+// detectStringType returns the type of a string value: DATE, TIMESTAMP, DECIMAL or VARCHAR. Numbers with
+// leading zeros, like zip codes, stay VARCHAR.
+func detectStringType(value string) *jsonType {
+	switch {
+	case isoDatePattern.MatchString(value):
+		if _, err := time.Parse(dateLayout, value); err == nil {
+			return &jsonType{Kind: typeDate, Format: formatISODate}
+		}
+	case isoTimestampPattern.MatchString(value):
+		if _, err := parseTimestamp(value); err == nil {
+			return &jsonType{Kind: typeTimestamp, Format: formatISOTimestamp}
+		}
+	case numericStringPattern.MatchString(value):
+		integer, fraction, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+		if len(integer)+len(fraction) <= maxDecimalDigits {
+			return &jsonType{Kind: typeDecimal, Format: formatNumericString, IntegerDigits: len(integer), Scale: len(fraction)}
+		}
+	}
+	return &jsonType{Kind: typeVarchar}
+}
+
+// widenDetectedType merges two typed columns of different kinds. Dates and timestamps widen to TIMESTAMP,
+// anything else falls back to VARCHAR.
+func widenDetectedType(current, next *jsonType) *jsonType {
+	if (current.Kind == typeDate && next.Kind == typeTimestamp) || (current.Kind == typeTimestamp && next.Kind == typeDate) {
+		return &jsonType{Kind: typeTimestamp, Format: formatISOTimestamp}
+	}
+	return &jsonType{Kind: typeVarchar}
+}
+
+// widenSameKind merges the ranges of two types of the same kind. It may modify current.
+func widenSameKind(current, next *jsonType) *jsonType {
+	switch current.Kind {
+	case typeDecimal:
+		current.IntegerDigits = max(current.IntegerDigits, next.IntegerDigits)
+		current.Scale = max(current.Scale, next.Scale)
+		if current.IntegerDigits+current.Scale > maxDecimalDigits {
+			return &jsonType{Kind: typeVarchar}
+		}
+	case typeBigInt:
+		current.Min = min(current.Min, next.Min)
+		current.Max = max(current.Max, next.Max)
+	}
+	return current
+}
+
+// detectEpochMillis turns integer columns and fields whose name suggests a time into TIMESTAMPs when every value
+// is a plausible epoch in milliseconds.
+func detectEpochMillis(name string, t *jsonType) {
+	switch t.Kind {
+	case typeBigInt:
+		if isEpochName(name) && t.Min >= minEpochMillis && t.Max < maxEpochMillis {
+			t.Kind = typeTimestamp
+			t.Format = formatEpochMillis
+		}
+	case typeStruct:
+		for _, field := range t.Fields {
+			detectEpochMillis(field.Key, field.Type)
+		}
+	case typeList:
+		detectEpochMillis(name, t.Elem)
+	}
+}
+
+func isEpochName(name string) bool {
+	for _, word := range strings.Split(normalizeIdentifier(name), "_") {
+		if epochNameWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDetectedTypes lists the typed columns and nested fields found under path and the format their values
+// were parsed from, one per line.
+func describeDetectedTypes(path string, t *jsonType) []string {
+	switch t.Kind {
+	case typeStruct:
+		var lines []string
+		for _, field := range t.Fields {
+			lines = append(lines, describeDetectedTypes(path+"."+field.Name, field.Type)...)
+		}
+		return lines
+	case typeList:
+		return describeDetectedTypes(path+"[]", t.Elem)
+	}
+
+	if t.Format == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s is %s, parsed from %s", path, t.SQL(), t.Format)}
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	if parsed, err := time.Parse(dateLayout, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// decimalValue converts a numeric string into a DuckDB DECIMAL with the scale of the column.
+func decimalValue(t *jsonType, value string) (duckdb.Decimal, error) {
+	integer, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > t.Scale {
+		return duckdb.Decimal{}, fmt.Errorf("%q has more than %d decimal places", value, t.Scale)
+	}
+
+	unscaled, ok := new(big.Int).SetString(integer+fraction+strings.Repeat("0", t.Scale-len(fraction)), 10)
+	if !ok {
+		return duckdb.Decimal{}, fmt.Errorf("invalid number %q", value)
+	}
+	return duckdb.Decimal{Width: uint8(t.IntegerDigits + t.Scale), Scale: uint8(t.Scale), Value: unscaled}, nil
+}
+
+// resultValue converts DuckDB DECIMAL values in query results, including inside STRUCTs and LISTs, into exact
+// decimal strings so they print as numbers.
+func resultValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case duckdb.Decimal:
+		digits := new(big.Int).Abs(v.Value).String()
+		if len(digits) <= int(v.Scale) {
+			digits = strings.Repeat("0", int(v.Scale)-len(digits)+1) + digits
+		}
+		if v.Scale > 0 {
+			digits = digits[:len(digits)-int(v.Scale)] + "." + digits[len(digits)-int(v.Scale):]
+		}
+		if v.Value.Sign() < 0 {
+			digits = "-" + digits
+		}
+		return digits
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = resultValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = resultValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+const (
+	uploadStateUploading  = "uploading"
+	uploadStateProcessing = "processing" // Uploaded, the JSON is being loaded into DuckDB
+	uploadStateCompleted  = "completed"
+	uploadStateFailed     = "failed"
+
+	// Finished uploads are kept around long enough for clients to read the final state
+	uploadStatusTTL = 15 * time.Minute
+)
+
+// uploadKey identifies an upload. IDs are chosen by clients, so they are only unique per user.
+type uploadKey struct {
+	userID   string
+	uploadID string
+}
+
+type uploadStatus struct {
+	state    string
+	progress UploadProgress
+	chatID   string
+	err      string
+	updated  time.Time
+}
+
+// uploadTracker keeps the progress of in-flight uploads in memory so clients can poll it while the upload
+// request is still running.
+type uploadTracker struct {
+	mu      sync.Mutex
+	uploads map[uploadKey]*uploadStatus
+}
+
+func newUploadTracker() *uploadTracker {
+	return &uploadTracker{uploads: make(map[uploadKey]*uploadStatus)}
+}
+
+// start tracks a new upload. IDs still tracked are rejected, so an upload's status cannot be overwritten by
+// another upload reusing its ID.
+func (t *uploadTracker) start(key uploadKey) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.removeExpired(time.Now())
+	if _, ok := t.uploads[key]; ok {
+		return fmt.Errorf("uploadID %q is already in use", key.uploadID)
+	}
+	t.uploads[key] = &uploadStatus{state: uploadStateUploading, updated: time.Now()}
+	return nil
+}
+
+func (t *uploadTracker) setProgress(key uploadKey, progress UploadProgress) {
+	t.update(key, func(u *uploadStatus) { u.progress = progress })
+}
+
+func (t *uploadTracker) processing(key uploadKey) {
+	t.update(key, func(u *uploadStatus) { u.state = uploadStateProcessing })
+}
+
+func (t *uploadTracker) complete(key uploadKey, chatID string) {
+	t.update(key, func(u *uploadStatus) {
+		u.state = uploadStateCompleted
+		u.chatID = chatID
+	})
+}
+
+func (t *uploadTracker) fail(key uploadKey, err error) {
+	t.update(key, func(u *uploadStatus) {
+		u.state = uploadStateFailed
+		u.err = err.Error()
+	})
+}
+
+func (t *uploadTracker) update(key uploadKey, fn func(u *uploadStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.removeExpired(now)
+
+	u, ok := t.uploads[key]
+	if !ok {
+		u = &uploadStatus{}
+		t.uploads[key] = u
+	}
+	fn(u)
+	u.updated = now
+}
+
+// removeExpired forgets uploads that finished more than uploadStatusTTL ago. t.mu must be held.
+func (t *uploadTracker) removeExpired(now time.Time) {
+	for key, u := range t.uploads {
+		finished := u.state == uploadStateCompleted || u.state == uploadStateFailed
+		if finished && now.Sub(u.updated) > uploadStatusTTL {
+			delete(t.uploads, key)
+		}
+	}
+}
+
+func (t *uploadTracker) get(key uploadKey) (uploadStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u, ok := t.uploads[key]
+	if !ok {
+		return uploadStatus{}, false
+	}
+	return *u, true
+}
+
+func (s Server) GetUploadStatus(ctx context.Context, in *proto.GetUploadStatus_Request) (*proto.GetUploadStatus_Response, error) {
+	if in.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserID is required")
+	}
+
+	if in.UploadID == "" {
+		return nil, status.Error(codes.InvalidArgument, "UploadID is required")
+	}
+
+	upload, ok := s.Uploads.get(uploadKey{userID: in.UserID, uploadID: in.UploadID})
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Upload not found")
+	}
+
+	return &proto.GetUploadStatus_Response{
+		UploadID:       in.UploadID,
+		State:          upload.state,
+		BytesUploaded:  upload.progress.BytesUploaded,
+		TotalBytes:     upload.progress.TotalBytes,
+		PartsCompleted: int32(upload.progress.PartsCompleted),
+		TotalParts:     int32(upload.progress.TotalParts),
+		ChatID:         upload.chatID,
+		Error:          upload.err,
+	}, nil
+}
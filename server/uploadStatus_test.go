@@ -0,0 +1,43 @@
+package server
+
+import (
+	"JsonAI/proto"
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadIDsAreScopedToTheUser(t *testing.T) {
+	s := Server{Uploads: newUploadTracker()}
+	alice := uploadKey{userID: "alice", uploadID: "upload-1"}
+	bob := uploadKey{userID: "bob", uploadID: "upload-1"}
+
+	if err := s.Uploads.start(alice); err != nil {
+		t.Fatalf("Failed to start upload: %v", err)
+	}
+	if err := s.Uploads.start(bob); err != nil {
+		t.Fatalf("Another user could not use the same uploadID: %v", err)
+	}
+	s.Uploads.complete(alice, "chat-1")
+	s.Uploads.fail(bob, errors.New("upload failed"))
+
+	if err := s.Uploads.start(alice); err == nil {
+		t.Errorf("An uploadID in use was accepted again")
+	}
+
+	response, err := s.GetUploadStatus(context.Background(), &proto.GetUploadStatus_Request{UserID: "alice", UploadID: "upload-1"})
+	if err != nil {
+		t.Fatalf("Failed to get upload status: %v", err)
+	}
+	if response.State != uploadStateCompleted || response.ChatID != "chat-1" {
+		t.Errorf("Got state %q with chat %q, want the completed upload of alice", response.State, response.ChatID)
+	}
+
+	_, err = s.GetUploadStatus(context.Background(), &proto.GetUploadStatus_Request{UserID: "carol", UploadID: "upload-1"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Got %v for an upload of another user, want NotFound", err)
+	}
+}
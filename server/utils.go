@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -25,39 +26,57 @@ func cleanSQLGeneration(sqlQuery string) string {
 	return strings.TrimSpace(sqlQuery)
 }
 
-func genericResultsToString(results []map[string]interface{}) string {
-	var resultsString string
-	for _, result := range results {
-		var resultParts []string
-		for key, value := range result {
-			resultParts = append(resultParts, fmt.Sprintf("%s: %v", key, value))
-		}
-		resultsString += strings.Join(resultParts, ", ") + "\n"
+// getBucketAndKeyFromS3URL parses the object URLs produced by objectURL. It accepts s3:// URIs, virtual-hosted
+// URLs (bucket.s3.region.amazonaws.com/key or bucket.<endpoint host>/key) and path-style URLs
+// (s3.region.amazonaws.com/bucket/key or a custom endpoint such as localhost:9000/bucket/key). The style is told
+// from the host, the key may contain '/' either way. The key is unescaped only after splitting off the bucket, so
+// escaped '/' in the key stays part of it.
+func (c AwsConfig) getBucketAndKeyFromS3URL(rawURL string) (bucket, key string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid S3 URL format: %v", err)
 	}
 
-	return resultsString
-}
-
-func getBucketAndKeyFromS3URL(url string) (bucket, key string, err error) {
-	if !strings.HasPrefix(url, "https://") {
-		return "", "", fmt.Errorf("invalid S3 URL format")
+	path := strings.TrimPrefix(u.EscapedPath(), "/")
+	// URLs stored before keys were escaped may have the end of the file name parsed as query or fragment
+	if u.ForceQuery || u.RawQuery != "" {
+		path += "?" + u.RawQuery
 	}
+	if u.Fragment != "" {
+		path += "#" + u.EscapedFragment()
+	}
+	host := u.Hostname()
 
-	urlParts := strings.Split(strings.TrimPrefix(url, "https://"), "/")
-
-	// Handle cases where the URL might not have the correct structure
-	if len(urlParts) < 2 {
+	switch {
+	case u.Scheme == "s3":
+		bucket, key = host, path
+	case u.Scheme != "http" && u.Scheme != "https":
 		return "", "", fmt.Errorf("invalid S3 URL format")
+	case strings.HasSuffix(host, ".amazonaws.com") && !strings.HasPrefix(host, "s3.") && !strings.HasPrefix(host, "s3-"):
+		// Virtual-hosted style, the bucket name may itself contain dots
+		idx := strings.Index(host, ".s3.")
+		if idx == -1 {
+			idx = strings.Index(host, ".s3-")
+		}
+		if idx <= 0 {
+			return "", "", fmt.Errorf("invalid S3 URL format")
+		}
+		bucket, key = host[:idx], path
+	case c.Endpoint != "" && strings.HasSuffix(host, "."+c.endpointHost()):
+		// Virtual-hosted style on the custom endpoint, e.g. bucket.minio.local/key
+		bucket, key = strings.TrimSuffix(host, "."+c.endpointHost()), path
+	default:
+		// Path style, used by AWS path-style URLs and S3-compatible endpoints
+		bucket, key, _ = strings.Cut(path, "/")
 	}
 
-	hostParts := strings.Split(urlParts[0], ".")
-	if len(hostParts) < 4 || hostParts[1] != "s3" {
+	if bucket == "" || key == "" {
 		return "", "", fmt.Errorf("invalid S3 URL format")
 	}
-	bucket = hostParts[0]
-
-	// The key is the rest of the URL
-	key = strings.Join(urlParts[1:], "/")
 
+	key, err = url.PathUnescape(key)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid S3 URL format: %v", err)
+	}
 	return bucket, key, nil
 }
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestObjectURLRoundTrip(t *testing.T) {
+	configs := []struct {
+		name   string
+		config AwsConfig
+	}{
+		{"virtual-hosted", AwsConfig{Region: "eu-central-1", BucketName: "jai-uploads"}},
+		{"virtual-hosted us-east-1", AwsConfig{Region: "us-east-1", BucketName: "jai.uploads"}},
+		{"path style", AwsConfig{Region: "eu-central-1", BucketName: "jai-uploads", UsePathStyle: true}},
+		{"custom endpoint", AwsConfig{BucketName: "jai-uploads", Endpoint: "http://localhost:9000", UsePathStyle: true}},
+		{"custom endpoint virtual-hosted", AwsConfig{BucketName: "jai-uploads", Endpoint: "https://minio.local"}},
+		{"custom endpoint without scheme", AwsConfig{BucketName: "jai-uploads", Endpoint: "minio.local:9000"}},
+	}
+	keys := []string{
+		"data.json",
+		"u-report #1.json",
+		"u-what?.json",
+		"u-100%.json",
+		"sales 2024+q1 & q2.json",
+		"%41.json",
+		"dir/file.json",
+		"user-1/dir/nested file #2.duckdb",
+		"dir/a%2Fb.json",
+	}
+
+	for _, c := range configs {
+		for _, key := range keys {
+			url := c.config.objectURL(key)
+			bucket, parsedKey, err := c.config.getBucketAndKeyFromS3URL(url)
+			if err != nil {
+				t.Errorf("%s: failed to parse %q: %v", c.name, url, err)
+				continue
+			}
+			if bucket != c.config.BucketName || parsedKey != key {
+				t.Errorf("%s: %q parsed as bucket %q key %q, want %q %q", c.name, url, bucket, parsedKey, c.config.BucketName, key)
+			}
+		}
+	}
+}
+
+func TestGetBucketAndKeyFromS3URLUnescapedURLs(t *testing.T) {
+	tests := []struct {
+		url    string
+		bucket string
+		key    string
+	}{
+		{"s3://jai-uploads/user-1/data.json", "jai-uploads", "user-1/data.json"},
+		{"https://jai-uploads.s3.eu-central-1.amazonaws.com/user-1/u-report #1.json", "jai-uploads", "user-1/u-report #1.json"},
+		{"https://jai-uploads.s3.amazonaws.com/user-1/u-what?.json", "jai-uploads", "user-1/u-what?.json"},
+	}
+
+	for _, test := range tests {
+		bucket, key, err := (AwsConfig{}).getBucketAndKeyFromS3URL(test.url)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", test.url, err)
+			continue
+		}
+		if bucket != test.bucket || key != test.key {
+			t.Errorf("%q parsed as bucket %q key %q, want %q %q", test.url, bucket, key, test.bucket, test.key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"JsonAI/server"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// Run with: go test ./test -run '^$' -bench Ingest
+const benchmarkRecords = 20000
+
+func benchmarkJSON() []interface{} {
+	records := make([]interface{}, benchmarkRecords)
+	for i := range records {
+		records[i] = map[string]interface{}{
+			"id":       float64(i),
+			"name":     fmt.Sprintf("member %d", i),
+			"price":    float64(i%1000) + 0.99,
+			"active":   i%2 == 0,
+			"category": fmt.Sprintf("category %d", i%20),
+		}
+	}
+	return records
+}
+
+func benchmarkIngest(b *testing.B, ingest func(db *sql.DB, jsonData interface{}) error) {
+	jsonData := benchmarkJSON()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, err := sql.Open("duckdb", "")
+		if err != nil {
+			b.Fatalf("Failed to open DuckDB: %v", err)
+		}
+		b.StartTimer()
+
+		if err := ingest(db, jsonData); err != nil {
+			b.Fatalf("Failed to ingest JSON: %v", err)
+		}
+
+		b.StopTimer()
+		var count int
+		if err := db.QueryRow("SELECT count(*) FROM json_data").Scan(&count); err != nil || count != benchmarkRecords {
+			b.Fatalf("Expected %d rows, got %d: %v", benchmarkRecords, count, err)
+		}
+		db.Close()
+		b.StartTimer()
+	}
+
+	b.ReportMetric(float64(benchmarkRecords*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// BenchmarkIngestPerRowInsert measures the original ingestion, one INSERT statement per record.
+func BenchmarkIngestPerRowInsert(b *testing.B) {
+	benchmarkIngest(b, func(db *sql.DB, jsonData interface{}) error {
+		if err := createTableFromJSON(db, jsonData); err != nil {
+			return err
+		}
+		return insertDataIntoDuckDB(db, jsonData)
+	})
+}
+
+// BenchmarkIngestAppender measures the server's ingestion through DuckDB's appender in a single transaction.
+func BenchmarkIngestAppender(b *testing.B) {
+	benchmarkIngest(b, func(db *sql.DB, jsonData interface{}) error {
+		_, err := server.LoadJSONIntoDuckDB(db, jsonData, "nested", "")
+		return err
+	})
+}